@@ -4,7 +4,10 @@
 package airtable
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -13,6 +16,8 @@ import (
 	"path"
 	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/ratelimit"
@@ -22,7 +27,20 @@ var (
 	DefaultRootURL    = "https://api.airtable.com"
 	DefaultVersion    = "v0"
 	DefaultHTTPClient = http.DefaultClient
-	DefaultLimiter    = RateLimiter(5) // per second
+
+	// DefaultLimiter, if set, is the Limiter for clients that don't
+	// have one, shared by all of them whatever their base. It's nil by
+	// default, so clients without a Limiter share a limiter with the
+	// other clients for the same base in the process, since Airtable's
+	// rate limit is per base.
+	DefaultLimiter ratelimit.Limiter
+
+	// DefaultRateLimit is Airtable's rate limit, in requests per second
+	// per base.
+	DefaultRateLimit = 5
+
+	DefaultPageRetries      = 0
+	DefaultPageRetryBackoff = time.Second
 )
 
 // RateLimiter makes a new rate limiter using n as the number of
@@ -41,6 +59,8 @@ type QueryEncoder interface {
 }
 
 // Client represents an interface to communicate with the Airtable API.
+// It can be made as a struct literal or with NewClient, which checks the
+// configuration up front and returns an error instead of panicking.
 //
 // - APIKey: api key to use for each request. Requests will panic
 // if this is not set.
@@ -52,17 +72,157 @@ type QueryEncoder interface {
 //
 // - RootURL: root URL to use.
 //
+// - ContentURL: root URL of the content API, used for uploads.
+//
+// - Endpoint: preset for RootURL and ContentURL, used for either one
+// that isn't set. Defaults to PublicEndpoint.
+//
 // - HTTPClient: http.Client instance to use.
 // http.DefaultClient
 //
-// - Limit: max requests to make per second.
+// - Transport: RoundTripper to send requests through instead of
+// HTTPClient's own transport. This is where to install an RFC 7234
+// caching RoundTripper such as httpcache: GETs are sent without a body
+// or Content-Type and with the query in a stable order, so the URL is
+// a good cache key. The request ID header changes with each request but
+// Airtable doesn't vary responses on it. Requests served from a cache
+// still wait on the rate limiter.
+//
+// - Limiter: paces requests. Defaults to DefaultLimiter if it's set, or
+// else to a limiter allowing DefaultRateLimit requests per second that's
+// shared by every client for the same base.
+//
+// - BaseLimiter: paces requests to the base in coordination with other
+// processes, instead of Limiter. See SharedLimiter.
+//
+// - KeepRawJSON: keep the JSON each record was decoded from, available
+// from Record.RawJSON. Off by default since it holds on to extra memory.
+//
+// - KeepFieldsPresent: keep which fields were in the response each
+// record was decoded from, available from Record.FieldsPresent. Off by
+// default since lists of records that embed Record are then decoded a
+// record at a time, which is slower.
+//
+// - PageRetries: how many times List retries a page that failed because
+// of a network error or a server error, including a page whose
+// response was cut off part way while it was being read. Defaults to
+// DefaultPageRetries, which is 0, so pages aren't retried unless it's
+// set.
+//
+// - PageRetryBackoff: how long List waits before the first retry of a
+// page. The wait doubles with each retry. Defaults to
+// DefaultPageRetryBackoff.
+//
+// - ThrottlePolicy: what to do when the rate limit is exceeded, see
+// ThrottleFailFast and ThrottleQueue. Defaults to ThrottleFailFast.
+//
+// - ThrottlePenalty: how long ThrottleQueue holds requests after the
+// rate limit is exceeded. Defaults to DefaultThrottlePenalty.
+//
+// - ThrottleAttempts: how many times ThrottleQueue sends a request that
+// keeps being rejected because the rate limit was exceeded before
+// returning the error. Defaults to DefaultThrottleAttempts.
+//
+// - CoalesceGETs: when several goroutines make the same GET request at
+// the same time, send it once and give them all the result, so a hot
+// record or list doesn't use up the rate limit. The request carries on
+// while any of the goroutines is still waiting for it, even if the one
+// that made it first gives up, and is cancelled once they all have. Off
+// by default.
+//
+// - RecordCache: cache of records by ID used by Get and
+// LinkedRecords.Fetch. Off by default; see NewRecordCache.
+//
+// - ZeroFill: set the fields of a record to their zero values before
+// decoding a response into it, so fields that Airtable leaves out, like
+// unchecked checkboxes and empty numbers, are false or 0 even when
+// decoding into a record that already held values, as Get does.
+//
+// - OmitZero: leave fields that hold their zero value, like false and 0,
+// out of Create and Update requests, so they leave the cells alone
+// instead of unchecking or setting them to 0. By default every field is
+// sent unless its JSON tag says omitempty.
+//
+// - OnRateLimited: called whenever Airtable says the rate limit was
+// exceeded, with how long requests will be held back (0 with
+// ThrottleFailFast, since the request fails instead) and which attempt
+// at the request it was, counting from 1.
+//
+// - OnRetry: called before a failed request is retried, either a List
+// page retried because of PageRetries or a request ThrottleQueue sends
+// again after the rate limit was exceeded, with the request, the error
+// and the number of the retry, counting from 1.
+//
+// - ValidateSelects: check the values of single and multiple select
+// fields against the field's choices, from the cached schema, before
+// any method writes records, and return an ErrInvalidChoice listing the
+// allowed choices instead of sending the request. Without it, Airtable
+// rejects unknown choices, or creates them if the record asks for
+// typecast.
+//
+// - ValidateFormats: check the fields whose type has a Validate method
+// (see FieldValidator), like Email, PhoneNumber and URL, before Create
+// and Update, and return an ErrInvalidFormat instead of sending the
+// request. Without it, Airtable stores malformed values as they are.
+//
+// - SchemaTTL: how long the base schema is cached for Table.Describe
+// and other operations that need it. Defaults to DefaultSchemaTTL.
+//
+// - RequestIDHeader: header each request's ID is sent in. The ID is
+// taken from the context (see WithRequestID) or generated, and is
+// included in errors. Defaults to DefaultRequestIDHeader.
+//
+// - ArgErrors: return an ErrInvalidArg from methods passed a record or
+// list of the wrong type, a record that can't be encoded, or options or
+// fields naming fields the record doesn't have, instead of panicking
+// with it. Off by default, since those are programming errors, but
+// services that can't afford a panic can set it.
+//
+// - MaxResponseSize: the most bytes of a response body kept in memory.
+// Larger bodies, like pages of records with huge long text fields, are
+// written to a temporary file instead, and List decodes the records
+// from the file one at a time. List normally decodes pages as they
+// arrive, so this only matters for the pages it reads whole, with
+// Options.Prefetch or ListPages. Methods that return the body, like
+// Request, still read it into memory. 0, the default, means no limit.
 type Client struct {
-	APIKey     string
-	BaseID     string
-	Version    string
-	RootURL    string
-	HTTPClient *http.Client
-	Limiter    ratelimit.Limiter
+	APIKey            string
+	BaseID            string
+	Version           string
+	RootURL           string
+	ContentURL        string
+	Endpoint          Endpoint
+	HTTPClient        *http.Client
+	Transport         http.RoundTripper
+	Limiter           ratelimit.Limiter
+	BaseLimiter       BaseLimiter
+	KeepRawJSON       bool
+	KeepFieldsPresent bool
+	PageRetries       int
+	PageRetryBackoff  time.Duration
+	ThrottlePolicy    ThrottlePolicy
+	ThrottlePenalty   time.Duration
+	ThrottleAttempts  int
+	CoalesceGETs      bool
+	RecordCache       *RecordCache
+	ZeroFill          bool
+	OmitZero          bool
+	OnRateLimited     func(wait time.Duration, attempt int)
+	OnRetry           func(req RequestInfo, err error, attempt int)
+	ValidateSelects   bool
+	ValidateFormats   bool
+	SchemaTTL         time.Duration
+	RequestIDHeader   string
+	MaxResponseSize   int64
+	ArgErrors         bool
+
+	// setUp is 1 once checkSetup has set the client up. It's not a
+	// sync.Once so clients can still be copied, as Fleet does.
+	setUp     uint32
+	throttle  *throttle
+	schema    *schemaCache
+	flights   *flightGroup
+	linkCache *RecordCache
 }
 
 // Request makes an HTTP request to the Airtable API without a body. See
@@ -75,18 +235,54 @@ func (c *Client) Request(
 	return c.RequestWithBody(method, endpoint, options, http.NoBody)
 }
 
+// RequestContext is like Request but the request is bound to ctx.
+func (c *Client) RequestContext(
+	ctx context.Context,
+	method string,
+	endpoint string,
+	options QueryEncoder,
+) ([]byte, error) {
+	return c.RequestWithBodyContext(ctx, method, endpoint, options, http.NoBody)
+}
+
+// RequestInfo identifies a request for the client's hooks.
+type RequestInfo struct {
+	Method    string
+	URL       string
+	RequestID string
+}
+
 // ErrClientRequest is returned when the client runs into
-// problems making a request.
+// problems making a request. StatusCode is the HTTP status of the
+// response, or 0 if the request failed before there was one. RequestID
+// is the ID the request was sent with. Err is an APIError if Airtable
+// responded with an error.
 type ErrClientRequest struct {
-	Err    error
-	Method string
-	URL    string
+	Err        error
+	Method     string
+	URL        string
+	StatusCode int
+	RequestID  string
 }
 
 func (e ErrClientRequest) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("airtable client request error: %s %s (request id %s): %s", e.Method, e.URL, e.RequestID, e.Err)
+	}
 	return fmt.Sprintf("airtable client request error: %s %s: %s", e.Method, e.URL, e.Err)
 }
 
+// Unwrap returns the underlying error.
+func (e ErrClientRequest) Unwrap() error {
+	return e.Err
+}
+
+// temporary reports whether the request might succeed if it's made
+// again soon: it failed because of the network or a server error.
+func (e ErrClientRequest) temporary() bool {
+	return e.StatusCode == 0 || e.StatusCode >= 500
+}
+
 // RequestWithBody makes an HTTP request to the Airtable API. endpoint
 // will be combined with the client's RootlURL, Version and BaseID, to
 // create the complete URL. endpoint is expected to already be encoded;
@@ -99,58 +295,220 @@ func (c *Client) RequestWithBody(
 	options QueryEncoder,
 	body io.Reader,
 ) ([]byte, error) {
-	var err error
+	return c.RequestWithBodyContext(context.Background(), method, endpoint, options, body)
+}
 
+// RequestWithBodyContext is like RequestWithBody but the request is
+// bound to ctx. Canceling ctx aborts the request.
+func (c *Client) RequestWithBodyContext(
+	ctx context.Context,
+	method string,
+	endpoint string,
+	options QueryEncoder,
+	body io.Reader,
+) ([]byte, error) {
 	// finish setup or panic if the client isn't configured correctly
 	c.checkSetup()
 
 	if options == nil {
 		options = url.Values{}
 	}
-	url := c.makeURL(endpoint, options)
-	req, err := http.NewRequest(method, url, body)
+	return c.doBytes(ctx, method, c.makeURL(endpoint, options), body)
+}
+
+// doBytes is like do but reads the whole body into memory, even if it
+// was spilled to a temporary file.
+func (c *Client) doBytes(
+	ctx context.Context,
+	method string,
+	url string,
+	body io.Reader,
+) ([]byte, error) {
+	return readAll(c.do(ctx, method, url, body))
+}
+
+// do makes the request to the complete URL and checks the response for
+// errors, coalescing identical GETs if the client is set to. The client
+// must already be set up.
+func (c *Client) do(
+	ctx context.Context,
+	method string,
+	url string,
+	body io.Reader,
+) (*responseBody, error) {
+	if c.CoalesceGETs && method == "GET" {
+		// a spilled body can't be shared, so coalesced responses are
+		// always read into memory.
+		bytes, err := c.flights.do(ctx, url, func(ctx context.Context) ([]byte, error) {
+			return readAll(c.throttled(ctx, method, url, body))
+		})
+		if _, ok := err.(ErrClientRequest); err != nil && !ok {
+			// the caller gave up waiting.
+			return nil, ErrClientRequest{
+				Err:    err,
+				URL:    url,
+				Method: method,
+			}
+		}
+		return &responseBody{data: bytes}, err
+	}
+	return c.throttled(ctx, method, url, body)
+}
 
+// throttled makes the request, handling rate limiting according to the
+// client's ThrottlePolicy.
+func (c *Client) throttled(
+	ctx context.Context,
+	method string,
+	url string,
+	body io.Reader,
+) (*responseBody, error) {
+	id := requestID(ctx)
+
+	if c.ThrottlePolicy != ThrottleQueue {
+		resp, err := c.send(ctx, method, url, body, id)
+		if reqErr, ok := err.(ErrClientRequest); ok && reqErr.StatusCode == http.StatusTooManyRequests {
+			if c.OnRateLimited != nil {
+				c.OnRateLimited(0, 1)
+			}
+		}
+		return resp, err
+	}
+
+	// the body has to be kept so the request can be sent again.
+	content, err := ioutil.ReadAll(body)
 	if err != nil {
 		return nil, ErrClientRequest{
-			Err:    err,
-			URL:    url,
-			Method: method,
+			Err:       err,
+			URL:       url,
+			Method:    method,
+			RequestID: id,
+		}
+	}
+	for attempt := 1; ; attempt++ {
+		if err := c.throttle.wait(ctx); err != nil {
+			return nil, ErrClientRequest{
+				Err:       err,
+				URL:       url,
+				Method:    method,
+				RequestID: id,
+			}
+		}
+		resp, err := c.send(ctx, method, url, bytesReader(content), id)
+		if reqErr, ok := err.(ErrClientRequest); ok && reqErr.StatusCode == http.StatusTooManyRequests {
+			if c.OnRateLimited != nil {
+				c.OnRateLimited(c.ThrottlePenalty, attempt)
+			}
+			c.throttle.penalize(c.ThrottlePenalty)
+			if attempt < c.ThrottleAttempts {
+				if c.OnRetry != nil {
+					info := RequestInfo{Method: reqErr.Method, URL: reqErr.URL, RequestID: reqErr.RequestID}
+					c.OnRetry(info, err, attempt)
+				}
+				continue
+			}
+		}
+		return resp, err
+	}
+}
+
+// send makes a single attempt at the request.
+func (c *Client) send(
+	ctx context.Context,
+	method string,
+	url string,
+	body io.Reader,
+	id string,
+) (*responseBody, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+
+	if err != nil {
+		return nil, ErrClientRequest{
+			Err:       err,
+			URL:       url,
+			Method:    method,
+			RequestID: id,
 		}
 	}
 
 	c.makeHeader(req)
+	if id != "" {
+		req.Header.Set(c.RequestIDHeader, id)
+	}
 
-	// Take() will block until we can safely make the next request
-	// without going over the rate limit
-	c.Limiter.Take()
+	// Wait() and Take() will block until we can safely make the next
+	// request without going over the rate limit
+	if c.BaseLimiter != nil {
+		if err := c.BaseLimiter.Wait(ctx, c.BaseID); err != nil {
+			return nil, ErrClientRequest{
+				Err:       err,
+				URL:       url,
+				Method:    method,
+				RequestID: id,
+			}
+		}
+	} else {
+		c.Limiter.Take()
+	}
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.httpClient().Do(req)
 	if err != nil {
 		return nil, ErrClientRequest{
-			Err:    err,
-			URL:    url,
-			Method: method,
+			Err:       err,
+			URL:       url,
+			Method:    method,
+			RequestID: id,
 		}
 	}
+	if streamedBody(ctx) && resp.StatusCode < 300 {
+		return &responseBody{stream: resp.Body}, nil
+	}
+	defer resp.Body.Close()
 
-	bytes, err := ioutil.ReadAll(resp.Body)
+	result, err := readBody(resp, c.MaxResponseSize)
 	if err != nil {
 		return nil, ErrClientRequest{
-			Err:    err,
-			URL:    url,
-			Method: method,
+			Err:       err,
+			URL:       url,
+			Method:    method,
+			RequestID: id,
 		}
 	}
 
-	if err = checkErrorResponse(bytes); err != nil {
-		return bytes, ErrClientRequest{
-			Err:    err,
-			URL:    url,
-			Method: method,
+	if err = result.checkError(resp.StatusCode); err != nil {
+		return result, ErrClientRequest{
+			Err:        err,
+			URL:        url,
+			Method:     method,
+			StatusCode: resp.StatusCode,
+			RequestID:  id,
 		}
 	}
 
-	return bytes, nil
+	return result, nil
+}
+
+// bodyBuffers holds buffers for reading response bodies, so reading a
+// large page doesn't grow a new buffer a few bytes at a time.
+var bodyBuffers = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// maxPooledBuffer is the largest buffer put back in bodyBuffers, so an
+// unusually large response doesn't stay in memory.
+const maxPooledBuffer = 4 << 20
+
+// httpClient returns the http.Client to send requests with: HTTPClient,
+// with its transport replaced by Transport if one is set. HTTPClient is
+// copied rather than changed since it might be shared, like
+// DefaultHTTPClient.
+func (c *Client) httpClient() *http.Client {
+	if c.Transport == nil {
+		return c.HTTPClient
+	}
+	httpClient := *c.HTTPClient
+	httpClient.Transport = c.Transport
+	return &httpClient
 }
 
 // Table returns a new Table that will use this client and operate
@@ -162,13 +520,37 @@ func (c *Client) Table(name string) Table {
 	}
 }
 
+// bytesReader returns a reader for b, or http.NoBody if b is empty so
+// requests without a body are sent without one.
+func bytesReader(b []byte) io.Reader {
+	if len(b) == 0 {
+		return http.NoBody
+	}
+	return bytes.NewReader(b)
+}
+
 func (c *Client) makeHeader(r *http.Request) {
 	r.Header = http.Header{}
 	r.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
-	r.Header.Add("Content-Type", "application/json")
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Header.Add("Content-Type", "application/json")
+	}
 }
 
+// setupMu serializes setting clients up, so a client can be set up
+// lazily by the first of many goroutines to use it. Once a client is
+// set up, checkSetup doesn't take it.
+var setupMu sync.Mutex
+
 func (c *Client) checkSetup() {
+	if atomic.LoadUint32(&c.setUp) == 1 {
+		return
+	}
+	setupMu.Lock()
+	defer setupMu.Unlock()
+	if atomic.LoadUint32(&c.setUp) == 1 {
+		return
+	}
 	if c.BaseID == "" {
 		panic("airtable: Client missing BaseID")
 	}
@@ -181,12 +563,52 @@ func (c *Client) checkSetup() {
 	if c.Version == "" {
 		c.Version = DefaultVersion
 	}
+	if c.RootURL == "" {
+		c.RootURL = c.Endpoint.RootURL
+	}
 	if c.RootURL == "" {
 		c.RootURL = DefaultRootURL
 	}
+	if c.ContentURL == "" {
+		c.ContentURL = c.Endpoint.ContentURL
+	}
+	if c.ContentURL == "" {
+		c.ContentURL = DefaultContentURL
+	}
 	if c.Limiter == nil {
 		c.Limiter = DefaultLimiter
 	}
+	if c.Limiter == nil {
+		c.Limiter = limiterFor(c.BaseID)
+	}
+	if c.PageRetries == 0 {
+		c.PageRetries = DefaultPageRetries
+	}
+	if c.PageRetryBackoff == 0 {
+		c.PageRetryBackoff = DefaultPageRetryBackoff
+	}
+	if c.ThrottlePenalty == 0 {
+		c.ThrottlePenalty = DefaultThrottlePenalty
+	}
+	if c.ThrottleAttempts == 0 {
+		c.ThrottleAttempts = DefaultThrottleAttempts
+	}
+	if c.RequestIDHeader == "" {
+		c.RequestIDHeader = DefaultRequestIDHeader
+	}
+	if c.SchemaTTL == 0 {
+		c.SchemaTTL = DefaultSchemaTTL
+	}
+	if c.throttle == nil {
+		c.throttle = &throttle{}
+	}
+	if c.schema == nil {
+		c.schema = &schemaCache{}
+	}
+	if c.flights == nil {
+		c.flights = &flightGroup{}
+	}
+	atomic.StoreUint32(&c.setUp, 1)
 }
 
 func (c *Client) makeURL(resource string, options QueryEncoder) string {
@@ -197,19 +619,105 @@ func (c *Client) makeURL(resource string, options QueryEncoder) string {
 	return uri
 }
 
+// APIError is an error response from Airtable. It's wrapped by
+// ErrClientRequest; use errors.As to get at it. HTTPStatus is the status
+// of the response, Type the error type, e.g. NOT_FOUND or
+// INVALID_REQUEST_UNKNOWN, Message the explanation Airtable gave, if any,
+// and Body the raw response.
+//
+// Responses with a failing status but no error in the body, like a 503
+// from a proxy, are reported with only HTTPStatus and Body set.
+type APIError struct {
+	HTTPStatus int
+	Type       string
+	Message    string
+	Body       []byte
+}
+
+func (e APIError) Error() string {
+	switch {
+	case e.Type != "" && e.Message != "":
+		return fmt.Sprintf("%s: %s", e.Type, e.Message)
+	case e.Type != "":
+		return e.Type
+	case e.Message != "":
+		return e.Message
+	}
+	return fmt.Sprintf("HTTP %d %s", e.HTTPStatus, http.StatusText(e.HTTPStatus))
+}
+
+// The kinds of error Airtable responds with. An APIError matches one of
+// them with errors.Is, by its status or type, so callers can tell them
+// apart without matching strings:
+//
+//	if errors.Is(err, airtable.ErrNotFound) { ... }
+var (
+	// ErrNotFound is for a base, table or record that doesn't exist.
+	ErrNotFound = errors.New("airtable: not found")
+
+	// ErrUnauthorized is for a missing or invalid API key, or one
+	// without permission for the request.
+	ErrUnauthorized = errors.New("airtable: unauthorized")
+
+	// ErrRequestTooLarge is for a request body over Airtable's limit.
+	ErrRequestTooLarge = errors.New("airtable: request too large")
+
+	// ErrInvalidRequest is for a request Airtable couldn't parse or
+	// act on, e.g. because of an unknown field or a bad value.
+	ErrInvalidRequest = errors.New("airtable: invalid request")
+)
+
+// Is reports whether e is of the kind of target, one of ErrNotFound,
+// ErrUnauthorized, ErrRateLimited, ErrRequestTooLarge or
+// ErrInvalidRequest.
+func (e APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.HTTPStatus == http.StatusNotFound || strings.HasSuffix(e.Type, "NOT_FOUND")
+	case ErrUnauthorized:
+		return e.HTTPStatus == http.StatusUnauthorized || e.HTTPStatus == http.StatusForbidden
+	case ErrRateLimited:
+		return e.HTTPStatus == http.StatusTooManyRequests
+	case ErrRequestTooLarge:
+		return e.HTTPStatus == http.StatusRequestEntityTooLarge
+	case ErrInvalidRequest:
+		return e.HTTPStatus == http.StatusBadRequest || e.HTTPStatus == http.StatusUnprocessableEntity ||
+			strings.HasPrefix(e.Type, "INVALID_REQUEST")
+	}
+	return false
+}
+
 type genericErrorResponse struct {
-	Error interface{} `json:"error"`
+	Error json.RawMessage `json:"error"`
 }
 
-func checkErrorResponse(b []byte) error {
+// checkErrorResponse returns the error in the response b with the given
+// status, if there is one. The error is either a type, or an object with
+// a type and a message.
+func checkErrorResponse(b []byte, status int) error {
 	var generic genericErrorResponse
 	if err := json.Unmarshal(b, &generic); err != nil {
+		if status >= 400 {
+			return APIError{HTTPStatus: status, Body: b}
+		}
 		return fmt.Errorf("couldn't unmarshal response: %s", err)
 	}
-	if generic.Error == nil {
+	if len(generic.Error) == 0 || string(generic.Error) == "null" {
+		if status >= 400 {
+			return APIError{HTTPStatus: status, Body: b}
+		}
 		return nil
 	}
-	return fmt.Errorf("%s", generic.Error)
+	apiErr := APIError{HTTPStatus: status, Body: b}
+	if err := json.Unmarshal(generic.Error, &apiErr.Type); err != nil {
+		var detail struct {
+			Type    string
+			Message string
+		}
+		json.Unmarshal(generic.Error, &detail)
+		apiErr.Type, apiErr.Message = detail.Type, detail.Message
+	}
+	return apiErr
 }
 
 // Record is a convenience struct for anonymous inclusion in
@@ -217,6 +725,43 @@ func checkErrorResponse(b []byte) error {
 type Record struct {
 	ID          string
 	CreatedTime time.Time
+
+	// a pointer keeps Record comparable.
+	meta *recordMeta
+}
+
+// recordMeta holds what's known about the response a Record was decoded
+// from.
+type recordMeta struct {
+	fields map[string]bool
+	raw    json.RawMessage
+}
+
+// FieldsPresent returns the set of fields, by Airtable field name, that
+// were in the response the record was decoded from. Airtable leaves out
+// empty cells, so this tells a field that is empty apart from a field
+// that wasn't returned at all, e.g. because it wasn't in Options.Fields.
+// It's only kept when the client's KeepFieldsPresent is set; otherwise,
+// or if the record wasn't decoded from a response, it returns nil.
+func (r Record) FieldsPresent() map[string]bool {
+	if r.meta == nil || r.meta.fields == nil {
+		return nil
+	}
+	present := make(map[string]bool, len(r.meta.fields))
+	for name := range r.meta.fields {
+		present[name] = true
+	}
+	return present
+}
+
+// RawJSON returns the JSON the record was decoded from, including any
+// fields the record struct doesn't have. It's only kept when the
+// client's KeepRawJSON is set; otherwise it returns nil.
+func (r Record) RawJSON() json.RawMessage {
+	if r.meta == nil {
+		return nil
+	}
+	return r.meta.raw
 }
 
 // Fields is used in NewRecord for constructing new records.
@@ -236,6 +781,23 @@ func NewRecord(recordPtr interface{}, data Fields) {
 	ref := reflect.ValueOf(recordPtr).Elem()
 	typ := ref.Type()
 	fields := ref.FieldByName("Fields")
+	if fields.Type() == rawMessageType {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			panic(fmt.Sprintf("airtable.NewRecord: could not encode fields: %s", err))
+		}
+		fields.SetBytes(raw)
+		return
+	}
+	if fields.Kind() == reflect.Map {
+		if fields.IsNil() {
+			fields.Set(reflect.MakeMap(fields.Type()))
+		}
+		for k, v := range data {
+			fields.SetMapIndex(reflect.ValueOf(k).Convert(fields.Type().Key()), reflect.ValueOf(v))
+		}
+		return
+	}
 	for k, v := range data {
 		f := fields.FieldByName(k)
 		val := reflect.ValueOf(v)
@@ -251,7 +813,9 @@ func NewRecord(recordPtr interface{}, data Fields) {
 	}
 }
 
-type deleteResponse struct {
+// DeleteResult reports whether the record with the given ID was
+// deleted.
+type DeleteResult struct {
 	Deleted bool
 	ID      string
 }
@@ -259,44 +823,140 @@ type deleteResponse struct {
 // Table represents an table in a base and provides methods for
 // interacting with records in the table.
 type Table struct {
-	name   string
-	client *Client
+	name     string
+	client   *Client
+	links    map[string]LinkBinding
+	typecast bool
 }
 
 // Get looks up a record from the table by ID and stores in in the
 // object pointed to by recordPtr.
 func (t *Table) Get(id string, recordPtr interface{}) error {
+	return t.GetWithOptions(id, recordPtr, nil)
+}
+
+// GetWithOptions is like Get but sends the options the single-record
+// endpoint supports with the request: CellFormat, TimeZone, UserLocale,
+// ReturnFieldsByFieldID and Extra. The endpoint can't return only some
+// fields, so Fields is ignored, as are Sort, Filter and the other
+// options for listing. options may be nil. Records fetched with options
+// bypass the client's RecordCache.
+func (t *Table) GetWithOptions(id string, recordPtr interface{}, options *Options) error {
+	if options != nil {
+		opts := Options{
+			CellFormat:            options.CellFormat,
+			TimeZone:              options.TimeZone,
+			UserLocale:            options.UserLocale,
+			ReturnFieldsByFieldID: options.ReturnFieldsByFieldID,
+			Extra:                 options.Extra,
+		}
+		bytes, err := t.client.Request("GET", t.makePath(id), opts)
+		if err != nil {
+			return err
+		}
+		return t.client.decodeRecord(bytes, recordPtr)
+	}
+
+	cache := t.client.RecordCache
+	if cache != nil {
+		if raw, ok := cache.get(t.name, id); ok {
+			return t.client.decodeRecord(raw, recordPtr)
+		}
+	}
 	bytes, err := t.client.Request("GET", t.makePath(id), nil)
 	if err != nil {
 		return err
 	}
-	return json.Unmarshal(bytes, recordPtr)
+	if cache != nil {
+		cache.put(t.name, id, bytes)
+	}
+	return t.client.decodeRecord(bytes, recordPtr)
 }
 
+// Exists reports whether the table has a record with the given ID. A
+// record in the client's RecordCache is taken to exist without a
+// request; otherwise the record is fetched, and Airtable saying it
+// wasn't found is reported as false rather than an error.
+func (t *Table) Exists(id string) (bool, error) {
+	return t.ExistsContext(context.Background(), id)
+}
+
+// ExistsContext is like Exists but the request is bound to ctx.
+func (t *Table) ExistsContext(ctx context.Context, id string) (bool, error) {
+	if cache := t.client.RecordCache; cache != nil {
+		if _, ok := cache.get(t.name, id); ok {
+			return true, nil
+		}
+	}
+	_, err := t.client.RequestContext(ctx, "GET", t.makePath(id), nil)
+	if reqErr, ok := err.(ErrClientRequest); ok && reqErr.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ErrInvalidArg is the error, a programming error, for a record or list
+// of the wrong type, or another invalid argument, passed to a method.
+// Methods panic with it unless the client's ArgErrors is set, in which
+// case they return it.
+type ErrInvalidArg struct {
+	Arg    string
+	Reason string
+}
+
+func (e ErrInvalidArg) Error() string {
+	return fmt.Sprintf("airtable type error: %s %s", e.Arg, e.Reason)
+}
+
+// argError panics with err, unless it's nil or the client's ArgErrors is
+// set, in which case it's returned.
+func (t *Table) argError(err error) error {
+	if err != nil && !t.client.ArgErrors {
+		panic(err)
+	}
+	return err
+}
+
+// jsonArgError is the error for a record that op couldn't encode as
+// JSON.
+func jsonArgError(op string, err error) error {
+	return ErrInvalidArg{Arg: "recordPtr", Reason: fmt.Sprintf("can't be encoded as JSON for %s: %s", op, err)}
+}
+
+// validateRecordArg panics if recordPtr isn't a pointer to a record.
 func validateRecordArg(recordPtr interface{}) {
+	if err := checkRecordArg(recordPtr); err != nil {
+		panic(err)
+	}
+}
+
+// checkRecordArg checks that recordPtr is a pointer to a record.
+func checkRecordArg(recordPtr interface{}) error {
 	// must be:
 	// ... a pointer
 	typ := reflect.TypeOf(recordPtr)
+	if typ == nil {
+		return ErrInvalidArg{Arg: "recordPtr", Reason: "must be a pointer, got nil"}
+	}
 	recordPtrKind := typ.Kind()
 	if recordPtrKind != reflect.Ptr {
-		panic(fmt.Errorf("airtable type error: recordPtr must be a pointer, got %s", recordPtrKind))
+		return ErrInvalidArg{Arg: "recordPtr", Reason: fmt.Sprintf("must be a pointer, got %s", recordPtrKind)}
 	}
 
 	// ... to a struct
 	record := typ.Elem()
 	recordKind := record.Kind()
 	if recordKind != reflect.Struct {
-		panic(fmt.Errorf("airtable type error: recordPtr must point to a struct, got %s", recordKind))
+		return ErrInvalidArg{Arg: "recordPtr", Reason: fmt.Sprintf("must point to a struct, got %s", recordKind)}
 	}
 
-	// ... which has a field named "Fields" that's a struct
+	// ... which has a field named "Fields" that's a struct or a map
 	fields, ok := record.FieldByName("Fields")
 	if !ok {
-		panic(fmt.Errorf("airtable type error: recordPtr must point to a struct with field 'Fields'"))
+		return ErrInvalidArg{Arg: "recordPtr", Reason: "must point to a struct with field 'Fields'"}
 	}
-	fieldsKind := fields.Type.Kind()
-	if fieldsKind != reflect.Struct {
-		panic(fmt.Errorf("airtable type error: recordPtr must point to a struct with field 'Fields' that is a struct, got %s", fieldsKind))
+	if !isFieldsType(fields.Type) {
+		return ErrInvalidArg{Arg: "recordPtr", Reason: fmt.Sprintf("must point to a struct with field 'Fields' that is a struct, a map with string keys or a json.RawMessage, got %s", fields.Type)}
 	}
 
 	// ... an optional field named "Typecast" that's a bool
@@ -304,36 +964,96 @@ func validateRecordArg(recordPtr interface{}) {
 	if ok {
 		typecastKind := typecast.Type.Kind()
 		if typecastKind != reflect.Bool {
-			panic(fmt.Errorf("airtable type error: recordPtr must point to a struct with field 'Typecast' that is a bool, got %s", typecastKind))
+			return ErrInvalidArg{Arg: "recordPtr", Reason: fmt.Sprintf("must point to a struct with field 'Typecast' that is a bool, got %s", typecastKind)}
 		}
 	}
 
 	// ... and a field named "ID" that's a string
 	id, ok := record.FieldByName("ID")
 	if !ok {
-		panic(fmt.Errorf("airtable type error: recordPtr must point to a struct with field 'ID'"))
+		return ErrInvalidArg{Arg: "recordPtr", Reason: "must point to a struct with field 'ID'"}
 	}
 	idKind := id.Type.Kind()
 	if idKind != reflect.String {
-		panic(fmt.Errorf("airtable type error: recordPtr must point to a struct with field 'ID' that is a string, got %s", idKind))
+		return ErrInvalidArg{Arg: "recordPtr", Reason: fmt.Sprintf("must point to a struct with field 'ID' that is a string, got %s", idKind)}
 	}
+	return nil
+}
+
+// isFieldsType reports whether typ can be the type of a record's Fields:
+// a struct, a map with string keys, like Fields, for records whose
+// fields aren't known ahead of time, or a json.RawMessage, for fields
+// that are decoded later or passed on as they are.
+func isFieldsType(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct ||
+		typ.Kind() == reflect.Map && typ.Key().Kind() == reflect.String ||
+		typ == rawMessageType
 }
 
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
 // Update sends the updated record pointed to by recordPtr to the table
 func (t *Table) Update(recordPtr interface{}) error {
-	// panic if the recordPtr doesn't point to a record.
-	validateRecordArg(recordPtr)
+	return t.UpdateContext(context.Background(), recordPtr)
+}
+
+// UpdateContext is like Update but the requests, including any to
+// resolve bound links, are bound to ctx.
+func (t *Table) UpdateContext(ctx context.Context, recordPtr interface{}) error {
+	return t.update(ctx, "PATCH", "Update", recordPtr)
+}
+
+// Replace is like Update but replaces the whole record, so the cells of
+// fields that aren't sent are cleared. Fields left out of the request
+// because their JSON tag says omitempty, or because the client's
+// OmitZero is set, are cleared too.
+func (t *Table) Replace(recordPtr interface{}) error {
+	return t.ReplaceContext(context.Background(), recordPtr)
+}
+
+// ReplaceContext is like Replace but the requests, including any to
+// resolve bound links, are bound to ctx.
+func (t *Table) ReplaceContext(ctx context.Context, recordPtr interface{}) error {
+	return t.update(ctx, "PUT", "Replace", recordPtr)
+}
+
+// update sends the record pointed to by recordPtr with method, which is
+// PATCH or PUT. op names the method in errors.
+func (t *Table) update(ctx context.Context, method, op string, recordPtr interface{}) error {
+	if err := t.argError(checkRecordArg(recordPtr)); err != nil {
+		return err
+	}
 
 	id := getID(recordPtr)
 
-	// panic makeJSONBody errors because it's an upstream programming
-	// error that needs to be fixed, not a user input error or a network
-	// condition.
-	body, err := makeJSONBody(recordPtr)
+	if err := t.resolveLinks(ctx, recordPtr); err != nil {
+		return err
+	}
+	if t.client.ValidateSelects {
+		if err := t.validateSelects(ctx, recordPtr); err != nil {
+			return err
+		}
+	}
+	if t.client.ValidateFormats {
+		if err := validateFormats(recordPtr); err != nil {
+			return err
+		}
+	}
+
+	// makeJSONBody errors are treated as argument errors because
+	// they're upstream programming errors that need to be fixed, not
+	// user input errors or network conditions. Codec and range errors
+	// are the exception since they can be caused by the data in the
+	// record.
+	body, err := t.makeJSONBody(recordPtr)
+	if dataError(err) {
+		return err
+	}
 	if err != nil {
-		panic(fmt.Errorf("airtable.Table#Update: unable to create JSON (%s)", err))
+		return t.argError(jsonArgError(op, err))
 	}
-	_, err = t.client.RequestWithBody("PATCH", t.makePath(id), Options{}, body)
+	_, err = t.client.RequestWithBodyContext(ctx, method, t.makePath(id), Options{}, body)
+	t.invalidate(id)
 	if err != nil {
 		return err
 	}
@@ -344,41 +1064,69 @@ func (t *Table) Update(recordPtr interface{}) error {
 // recordPtr. On success, updates the ID and CreatedTime of the object
 // pointed to by recordPtr.
 //
-// recordPtr MUST have a Fields field that is a struct that can be
-// marshaled to JSON or this method will panic.
+// recordPtr MUST have a Fields field that is a struct or a map with
+// string keys that can be marshaled to JSON or this method will panic.
 func (t *Table) Create(recordPtr interface{}) error {
-	// panic if the recordPtr doesn't point to a record.
-	validateRecordArg(recordPtr)
+	return t.CreateContext(context.Background(), recordPtr)
+}
 
-	body, err := makeJSONBody(recordPtr)
+// CreateContext is like Create but the requests, including any to
+// resolve bound links, are bound to ctx.
+func (t *Table) CreateContext(ctx context.Context, recordPtr interface{}) error {
+	if err := t.argError(checkRecordArg(recordPtr)); err != nil {
+		return err
+	}
 
-	// panic if we can't create the JSON because it's an upstream
-	// programming error that needs to be fixed, not a user input error
-	// or a network condition.
+	if err := t.resolveLinks(ctx, recordPtr); err != nil {
+		return err
+	}
+	if t.client.ValidateSelects {
+		if err := t.validateSelects(ctx, recordPtr); err != nil {
+			return err
+		}
+	}
+	if t.client.ValidateFormats {
+		if err := validateFormats(recordPtr); err != nil {
+			return err
+		}
+	}
+
+	body, err := t.makeJSONBody(recordPtr)
+
+	// treat it as an argument error if we can't create the JSON because
+	// it's an upstream programming error that needs to be fixed, not a
+	// user input error or a network condition. Codec and range errors
+	// are the exception since they can be caused by the data in the
+	// record.
+	if dataError(err) {
+		return err
+	}
 	if err != nil {
-		panic(fmt.Errorf("airtable.Table#Create: unable to create JSON (%s)", err))
+		return t.argError(jsonArgError("Create", err))
 	}
 
-	res, err := t.client.RequestWithBody("POST", t.makePath(""), Options{}, body)
+	res, err := t.client.RequestWithBodyContext(ctx, "POST", t.makePath(""), Options{}, body)
 	if err != nil {
 		return err
 	}
-	return json.Unmarshal(res, recordPtr)
+	return t.client.decodeRecord(res, recordPtr)
 }
 
 // Delete removes a record from the table. On success, ID and
 // CreatedTime of the object pointed to by recordPtr are removed.
 func (t *Table) Delete(recordPtr interface{}) error {
-	// panic if the recordPtr doesn't point to a record.
-	validateRecordArg(recordPtr)
+	if err := t.argError(checkRecordArg(recordPtr)); err != nil {
+		return err
+	}
 
 	id := getID(recordPtr)
 
 	res, err := t.client.Request("DELETE", t.makePath(id), Options{})
+	t.invalidate(id)
 	if err != nil {
 		return fmt.Errorf("airtable.Table#Delete: request error %s", err)
 	}
-	deleted := deleteResponse{}
+	deleted := DeleteResult{}
 	if err := json.Unmarshal(res, &deleted); err != nil {
 		return fmt.Errorf("airtable.Table#Delete: could not unpack request %s", err)
 	}
@@ -389,8 +1137,9 @@ func (t *Table) Delete(recordPtr interface{}) error {
 	return nil
 }
 
-// makeResponseContainer returns a new struct based on listPtr that can
-// contain the response from a list query to an airtable. For example:
+// responseContainerType returns the type of a struct based on listType
+// that can contain the response from a list query to an airtable. For
+// example:
 //
 //  type BookRecord struct {
 //		airtable.Record
@@ -399,35 +1148,29 @@ func (t *Table) Delete(recordPtr interface{}) error {
 //			Author string
 //		}
 //  }
-//  listPtr := &[]BookRecord{}
 //
-// Passing the above listPtr to makeResponseContainer will dynamically
-// create a struct that looks like this:
+// Passing []BookRecord to responseContainerType will dynamically create
+// a struct type that looks like this:
 //
 //  struct {
 //		Records []BookRecord
 //		Offset  string
 //  }
-func makeResponseContainer(listPtr interface{}) reflect.Value {
-	responseType := reflect.StructOf([]reflect.StructField{
-		{Name: "Records", Type: reflect.TypeOf(listPtr).Elem()},
+//
+// The types are cached since they're costly to build.
+func responseContainerType(listType reflect.Type) reflect.Type {
+	if typ, ok := responseContainerTypes.Load(listType); ok {
+		return typ.(reflect.Type)
+	}
+	typ := reflect.StructOf([]reflect.StructField{
+		{Name: "Records", Type: listType},
 		{Name: "Offset", Type: reflect.TypeOf("string")},
 	})
-	return reflect.New(responseType)
+	responseContainerTypes.Store(listType, typ)
+	return typ
 }
 
-func getOffset(listResponseContainer reflect.Value) string {
-	return listResponseContainer.Elem().FieldByName("Offset").String()
-}
-
-func appendRecordsToList(listPtr interface{}, results reflect.Value) {
-	var (
-		original = reflect.ValueOf(listPtr).Elem()
-		records  = results.Elem().FieldByName("Records")
-		updated  = reflect.AppendSlice(original, records)
-	)
-	original.Set(updated)
-}
+var responseContainerTypes sync.Map
 
 // getRecordType will get the base element type from a pointer to a
 // slice. For example: getRecordType(*[]string) -> string
@@ -435,50 +1178,63 @@ func getRecordType(ps interface{}) reflect.Type {
 	return reflect.TypeOf(ps).Elem().Elem()
 }
 
+// validateListArg panics if listPtr isn't a pointer to a slice of
+// records.
 func validateListArg(listPtr interface{}) {
+	if err := checkListArg(listPtr); err != nil {
+		panic(err)
+	}
+}
+
+// checkListArg checks that listPtr is a pointer to a slice of records.
+func checkListArg(listPtr interface{}) error {
 	// must be:
 	// ... a pointer
 	typ := reflect.TypeOf(listPtr)
+	if typ == nil {
+		return ErrInvalidArg{Arg: "listPtr", Reason: "must be a pointer, got nil"}
+	}
 	listPtrKind := typ.Kind()
 	if listPtrKind != reflect.Ptr {
-		panic(fmt.Errorf("airtable type error: listPtr must be a pointer, got %s", listPtrKind))
+		return ErrInvalidArg{Arg: "listPtr", Reason: fmt.Sprintf("must be a pointer, got %s", listPtrKind)}
 	}
 
 	// ... to a slice
 	list := typ.Elem()
 	listKind := list.Kind()
 	if listKind != reflect.Slice {
-		panic(fmt.Errorf("airtable type error: listPtr must point to a slice, got %s", listKind))
+		return ErrInvalidArg{Arg: "listPtr", Reason: fmt.Sprintf("must point to a slice, got %s", listKind)}
 	}
 
 	// ... whose elements are structs
 	elem := list.Elem()
 	elemKind := elem.Kind()
 	if elemKind != reflect.Struct {
-		panic(fmt.Errorf("airtable type error: listPtr must point to a slice of structs, got %s", elemKind))
+		return ErrInvalidArg{Arg: "listPtr", Reason: fmt.Sprintf("must point to a slice of structs, got %s", elemKind)}
 	}
 
-	// ... the structs have a field named "Fields" that's a struct
+	// ... the structs have a field named "Fields" that's a struct or a
+	// map
 	fields, ok := elem.FieldByName("Fields")
 	if !ok {
-		panic(fmt.Errorf("airtable type error: listPtr must point to a slice of structs with field 'Fields'"))
+		return ErrInvalidArg{Arg: "listPtr", Reason: "must point to a slice of structs with field 'Fields'"}
 	}
 
-	fieldsKind := fields.Type.Kind()
-	if fieldsKind != reflect.Struct {
-		panic(fmt.Errorf("airtable type error: listPtr must point to a slice of structs with field 'Fields' that is a struct, got %s", fieldsKind))
+	if !isFieldsType(fields.Type) {
+		return ErrInvalidArg{Arg: "listPtr", Reason: fmt.Sprintf("must point to a slice of structs with field 'Fields' that is a struct, a map with string keys or a json.RawMessage, got %s", fields.Type)}
 	}
 
 	// ... and a field named "ID" that's a string
 	id, ok := elem.FieldByName("ID")
 	if !ok {
-		panic(fmt.Errorf("airtable type error: listPtr must point to a slice of structs with field 'ID'"))
+		return ErrInvalidArg{Arg: "listPtr", Reason: "must point to a slice of structs with field 'ID'"}
 	}
 
 	idKind := id.Type.Kind()
 	if idKind != reflect.String {
-		panic(fmt.Errorf("airtable type error: listPtr must point to a slice of structs with field 'ID' that is a string, got %s", idKind))
+		return ErrInvalidArg{Arg: "listPtr", Reason: fmt.Sprintf("must point to a slice of structs with field 'ID' that is a string, got %s", idKind)}
 	}
+	return nil
 }
 
 // List queries the table for list of records and stores it in the
@@ -487,6 +1243,14 @@ func validateListArg(listPtr interface{}) {
 // be overriden by using the MaxRecords option. See Options for a
 // complete list of the options that are supported.
 //
+// A page that fails because of a network or server error is retried
+// with backoff if Client.PageRetries is set. If it still fails, or hits
+// the rate limit, List returns an ErrListInterrupted with the offset of
+// the page so the listing can be resumed instead of restarted. Other
+// errors, like an invalid formula, are returned as they are. When List
+// returns an error, the list is left as it was unless Options.Partial
+// is set, in which case it holds the records fetched before the error.
+//
 // listPtr must be a pointer to a slice of records, which are structs
 // that contain, at a minimum, `ID string` and `Fields struct {...}`
 // fields. For example:
@@ -500,10 +1264,47 @@ func validateListArg(listPtr interface{}) {
 //  }
 //  listPtr := &[]BookRecord{}
 //
+// For tables whose fields aren't known ahead of time, Fields can be a
+// map with string keys instead, like Fields, which holds every cell of
+// the record by its Airtable name. It can also be a json.RawMessage,
+// which holds the fields object of the response as it is and is sent
+// as it is on writes, to defer decoding it or to pass it on.
+//
 // This will be validated and cause a panic at runtime if listPtr is the
 // wrong type.
 func (t *Table) List(listPtr interface{}, options *Options) error {
-	validateListArg(listPtr)
+	return t.ListContext(context.Background(), listPtr, options)
+}
+
+// ListContext is like List but the requests are bound to ctx.
+func (t *Table) ListContext(ctx context.Context, listPtr interface{}, options *Options) error {
+	return t.listPages(ctx, listPtr, options, nil)
+}
+
+// ListPages is like List but calls fn after each page, so a long listing
+// can report progress, save a checkpoint or be stopped between pages.
+// fn gets the page's response body and a pointer to a slice, of the same
+// type as the list, holding the page's records; the records are also
+// appended to the list. The body is nil if the page was spilled to disk
+// (see Client.MaxResponseSize). When fn is called, options.Offset is
+// already the offset of the next page.
+//
+// If fn returns an error, ListPages stops and returns it, and the list
+// is handled as if the next page had failed.
+func (t *Table) ListPages(listPtr interface{}, options *Options, fn func(page []byte, records interface{}) error) error {
+	return t.ListPagesContext(context.Background(), listPtr, options, fn)
+}
+
+// ListPagesContext is like ListPages but the requests are bound to ctx.
+func (t *Table) ListPagesContext(ctx context.Context, listPtr interface{}, options *Options, fn func(page []byte, records interface{}) error) error {
+	return t.listPages(ctx, listPtr, options, fn)
+}
+
+// listPages implements List and ListPages. fn may be nil.
+func (t *Table) listPages(ctx context.Context, listPtr interface{}, options *Options, fn func(page []byte, records interface{}) error) error {
+	if err := t.argError(checkListArg(listPtr)); err != nil {
+		return err
+	}
 
 	if options == nil {
 		options = &Options{}
@@ -512,26 +1313,220 @@ func (t *Table) List(listPtr interface{}, options *Options) error {
 	// for "sort" and "fields" we need to have access to the type of
 	// record so we can look up the JSON names of the fields.
 	options.setType(getRecordType(listPtr))
+	if err := t.argError(options.check()); err != nil {
+		return err
+	}
 
+	// unless partial results are wanted, errors leave the list the way
+	// it was.
+	list := reflect.ValueOf(listPtr).Elem()
+	original := list.Len()
+	fail := func(err error) error {
+		if !options.Partial {
+			list.Set(list.Slice(0, original))
+		}
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// pages are decoded straight from the connection, unless the whole
+	// body is needed to prefetch the next page or for fn.
+	pageCtx := ctx
+	if !options.Prefetch && fn == nil {
+		pageCtx = withStreamedBody(ctx)
+	}
+
+	decoder := t.client.newListDecoder(listPtr)
+	var retries int
+	var backoff time.Duration
+	var next <-chan prefetchedPage
+	stop := func(err error) error {
+		if next != nil {
+			cancel()
+			(<-next).body.close()
+		}
+		return fail(err)
+	}
 	for {
-		container := makeResponseContainer(listPtr)
-		bytes, err := t.client.Request("GET", t.makePath(""), options)
+		var body *responseBody
+		var err error
+		if next != nil {
+			page := <-next
+			body, err, next = page.body, page.err, nil
+		} else {
+			body, err = t.client.requestPage(pageCtx, t.makePath(""), options)
+		}
 		if err != nil {
-			return err
+			body.close()
+			return fail(listError(options.Offset, err))
+		}
+		if options.Prefetch {
+			if offset := body.offset(); offset != "" {
+				ahead := *options
+				ahead.Offset = offset
+				next = t.client.prefetchPage(ctx, t.makePath(""), ahead)
+			}
 		}
-		err = json.Unmarshal(bytes, container.Interface())
+		start := list.Len()
+		streamed := body.stream != nil
+		offset, err := decoder.read(body)
+		body.close()
 		if err != nil {
-			return err
+			// the page may have been cut off part way, so none of its
+			// records are kept, and if the connection failed the page
+			// can be retried.
+			list.Set(list.Slice(0, start))
+			if streamed && readFailed(err) {
+				if retries < t.client.PageRetries && ctx.Err() == nil {
+					if retries == 0 {
+						backoff = t.client.PageRetryBackoff
+					}
+					retries++
+					if t.client.OnRetry != nil {
+						info := RequestInfo{Method: "GET", URL: t.client.makeURL(t.makePath(""), options)}
+						t.client.OnRetry(info, err, retries)
+					}
+					select {
+					case <-ctx.Done():
+					case <-time.After(backoff):
+					}
+					backoff *= 2
+					continue
+				}
+				err = ErrListInterrupted{Offset: options.Offset, Err: err}
+			}
+			return stop(err)
+		}
+		retries = 0
+		options.Offset = offset
+		if fn != nil {
+			page := reflect.New(list.Type())
+			page.Elem().Set(list.Slice(start, list.Len()))
+			if err := fn(body.data, page.Interface()); err != nil {
+				return stop(err)
+			}
 		}
-		appendRecordsToList(listPtr, container)
-		options.offset = getOffset(container)
-		if options.offset == "" {
+		if options.Offset == "" {
 			break
 		}
 	}
 	return nil
 }
 
+// ListPage gets a single page of records, starting at options.Offset,
+// and appends them to the list pointed to by listPtr. It returns the
+// offset of the next page, or "" if this was the last one. Unlike List,
+// it doesn't change options, so callers can keep the offset wherever
+// they like, such as on disk between runs, and pass it back in to get
+// the next page.
+//
+// A page that fails because of a temporary problem is retried as in
+// List. listPtr has the same requirements as in List.
+func (t *Table) ListPage(listPtr interface{}, options *Options) (string, error) {
+	return t.ListPageContext(context.Background(), listPtr, options)
+}
+
+// ListPageContext is like ListPage but the request is bound to ctx.
+func (t *Table) ListPageContext(ctx context.Context, listPtr interface{}, options *Options) (string, error) {
+	if err := t.argError(checkListArg(listPtr)); err != nil {
+		return "", err
+	}
+
+	opts := Options{}
+	if options != nil {
+		opts = *options
+	}
+	opts.setType(getRecordType(listPtr))
+	if err := t.argError(opts.check()); err != nil {
+		return "", err
+	}
+
+	body, err := t.client.requestPage(withStreamedBody(ctx), t.makePath(""), &opts)
+	defer body.close()
+	if err != nil {
+		return "", err
+	}
+	return t.client.newListDecoder(listPtr).read(body)
+}
+
+// ErrListInterrupted is returned by List when a page fails because of a
+// temporary problem, even after being retried. Offset is the offset of
+// the page that failed; set it as the Offset in the Options passed to
+// List to resume from that page. The records from the pages before it
+// are only kept in the list if Options.Partial is set.
+type ErrListInterrupted struct {
+	Offset string
+	Err    error
+}
+
+func (e ErrListInterrupted) Error() string {
+	return fmt.Sprintf("airtable: list interrupted at offset %q: %s", e.Offset, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e ErrListInterrupted) Unwrap() error {
+	return e.Err
+}
+
+// listError returns the error for a page at offset that couldn't be
+// requested: an ErrListInterrupted if it failed because of a temporary
+// problem, so the listing can be resumed from the page, or err as it is.
+func listError(offset string, err error) error {
+	reqErr, ok := err.(ErrClientRequest)
+	if ok && (reqErr.temporary() || reqErr.StatusCode == http.StatusTooManyRequests) {
+		return ErrListInterrupted{Offset: offset, Err: err}
+	}
+	return err
+}
+
+// requestPage requests one page of a listing, retrying with backoff if
+// it fails because of a temporary problem.
+//
+// The body may have been spilled to a temporary file; the caller must
+// close it.
+func (c *Client) requestPage(ctx context.Context, endpoint string, options QueryEncoder) (*responseBody, error) {
+	c.checkSetup()
+	backoff := c.PageRetryBackoff
+	for attempt := 0; ; attempt++ {
+		body, err := c.do(ctx, "GET", c.makeURL(endpoint, options), http.NoBody)
+		reqErr, ok := err.(ErrClientRequest)
+		if err == nil || !ok || !reqErr.temporary() || attempt >= c.PageRetries || ctx.Err() != nil {
+			return body, err
+		}
+		body.close()
+		if c.OnRetry != nil {
+			info := RequestInfo{Method: reqErr.Method, URL: reqErr.URL, RequestID: reqErr.RequestID}
+			c.OnRetry(info, err, attempt+1)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// prefetchedPage is the result of prefetchPage.
+type prefetchedPage struct {
+	body *responseBody
+	err  error
+}
+
+// prefetchPage requests a page in the background. The result must be
+// received from the channel, and its body closed, even if ctx is
+// canceled.
+func (c *Client) prefetchPage(ctx context.Context, endpoint string, options Options) <-chan prefetchedPage {
+	next := make(chan prefetchedPage, 1)
+	go func() {
+		body, err := c.requestPage(ctx, endpoint, &options)
+		next <- prefetchedPage{body, err}
+	}()
+	return next
+}
+
 func (t *Table) makePath(id string) string {
 	name := url.PathEscape(t.name)
 	if id == "" {
@@ -557,29 +1552,58 @@ func markAsDeleted(recordPtr interface{}) {
 
 // makeJSONBody returns an io.Reader prepared for use in either Create
 // or Update operations.
-func makeJSONBody(recordPtr interface{}) (io.Reader, error) {
-	f := getFields(recordPtr)
-	b, err := json.Marshal(f)
+func (t *Table) makeJSONBody(recordPtr interface{}) (io.Reader, error) {
+	b, err := t.client.encodeRecordFields(recordPtr)
 	if err != nil {
 		return nil, err
 	}
-	t := getTypecast(recordPtr)
-	jsonstr := fmt.Sprintf(`{"fields": %s, "typecast": %t}`, b, t)
+	jsonstr := fmt.Sprintf(`{"fields": %s, "typecast": %t}`, b, t.typecastFor(recordPtr))
 	body := strings.NewReader(jsonstr)
 	return body, nil
 }
 
+// encodeRecordFields encodes the Fields of the record pointed to by
+// recordPtr as they're sent to the API.
+func (c *Client) encodeRecordFields(recordPtr interface{}) ([]byte, error) {
+	f := reflect.ValueOf(getFields(recordPtr))
+	b, err := encodeFields(f)
+	if err != nil {
+		return nil, err
+	}
+	if c.OmitZero {
+		if b, err = omitZeroFields(f, b); err != nil {
+			return nil, err
+		}
+	}
+	return applyFieldTags(f, b)
+}
+
 func getFields(ptr interface{}) interface{} {
 	return reflect.ValueOf(ptr).Elem().FieldByName("Fields").Interface()
 }
 
-func getTypecast(ptr interface{}) interface{} {
+func getTypecast(ptr interface{}) bool {
 	if reflect.ValueOf(ptr).Elem().FieldByName("Typecast").IsValid() {
-		return reflect.ValueOf(ptr).Elem().FieldByName("Typecast").Interface()
+		return reflect.ValueOf(ptr).Elem().FieldByName("Typecast").Bool()
 	}
 	return false
 }
 
+// typecastFor reports whether writing the record pointed to by
+// recordPtr should ask Airtable to typecast: if the table is set to, or
+// the record has a Typecast field that's true.
+func (t *Table) typecastFor(recordPtr interface{}) bool {
+	return t.typecast || getTypecast(recordPtr)
+}
+
+// SetTypecast sets whether Create, Update and the other methods that
+// write records ask Airtable to convert string values to the type of
+// the field, for example creating select options and parsing dates.
+// Records can also ask for typecast themselves with a Typecast field.
+func (t *Table) SetTypecast(on bool) {
+	t.typecast = on
+}
+
 func getID(ptr interface{}) string {
 	return reflect.ValueOf(ptr).Elem().FieldByName("ID").String()
 }
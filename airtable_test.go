@@ -0,0 +1,21 @@
+package airtable_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/brianloveswords/airtable"
+)
+
+// newTestClient returns a client that sends all of its requests to
+// handler instead of the Airtable API. The caller must call close when
+// it's done with the client.
+func newTestClient(handler http.HandlerFunc) (client *airtable.Client, close func()) {
+	server := httptest.NewServer(handler)
+	return &airtable.Client{
+		APIKey:  "keyTEST",
+		BaseID:  "appTEST",
+		RootURL: server.URL,
+		Limiter: airtable.RateLimiter(0),
+	}, server.Close
+}
@@ -0,0 +1,88 @@
+package airtable_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestAPIError(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v0/appTEST/Tasks/recBAD":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"error": {"type": "INVALID_REQUEST_UNKNOWN", "message": "Invalid request"}}`)
+		case "/v0/appTEST/Tasks/recGONE":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": "NOT_FOUND"}`)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	defer done()
+	table := client.Table("Tasks")
+
+	tests := []struct {
+		id   string
+		want airtable.APIError
+	}{
+		{"recBAD", airtable.APIError{HTTPStatus: 422, Type: "INVALID_REQUEST_UNKNOWN", Message: "Invalid request"}},
+		{"recGONE", airtable.APIError{HTTPStatus: 404, Type: "NOT_FOUND"}},
+		{"recDOWN", airtable.APIError{HTTPStatus: 503}},
+	}
+	for _, test := range tests {
+		err := table.Get(test.id, &taskRecord{})
+		var apiErr airtable.APIError
+		if !errors.As(err, &apiErr) {
+			t.Errorf("%s: expected an APIError, got %v", test.id, err)
+			continue
+		}
+		if apiErr.HTTPStatus != test.want.HTTPStatus || apiErr.Type != test.want.Type || apiErr.Message != test.want.Message {
+			t.Errorf("%s: unexpected error %+v", test.id, apiErr)
+		}
+	}
+
+	err := table.Get("recBAD", &taskRecord{})
+	var apiErr airtable.APIError
+	if errors.As(err, &apiErr) && string(apiErr.Body) != `{"error": {"type": "INVALID_REQUEST_UNKNOWN", "message": "Invalid request"}}` {
+		t.Errorf("unexpected body %s", apiErr.Body)
+	}
+}
+
+func TestAPIErrorKinds(t *testing.T) {
+	tests := []struct {
+		err  airtable.APIError
+		want error
+	}{
+		{airtable.APIError{HTTPStatus: 404, Type: "NOT_FOUND"}, airtable.ErrNotFound},
+		{airtable.APIError{HTTPStatus: 200, Type: "MODEL_ID_NOT_FOUND"}, airtable.ErrNotFound},
+		{airtable.APIError{HTTPStatus: 401, Type: "AUTHENTICATION_REQUIRED"}, airtable.ErrUnauthorized},
+		{airtable.APIError{HTTPStatus: 403, Type: "INVALID_PERMISSIONS"}, airtable.ErrUnauthorized},
+		{airtable.APIError{HTTPStatus: 429, Type: "RATE_LIMIT_REACHED"}, airtable.ErrRateLimited},
+		{airtable.APIError{HTTPStatus: 413}, airtable.ErrRequestTooLarge},
+		{airtable.APIError{HTTPStatus: 422, Type: "INVALID_VALUE_FOR_COLUMN"}, airtable.ErrInvalidRequest},
+		{airtable.APIError{HTTPStatus: 400, Type: "INVALID_REQUEST_UNKNOWN"}, airtable.ErrInvalidRequest},
+	}
+	kinds := []error{airtable.ErrNotFound, airtable.ErrUnauthorized, airtable.ErrRateLimited,
+		airtable.ErrRequestTooLarge, airtable.ErrInvalidRequest}
+	for _, test := range tests {
+		for _, kind := range kinds {
+			if got := errors.Is(test.err, kind); got != (kind == test.want) {
+				t.Errorf("%+v: errors.Is(%v) = %v", test.err, kind, got)
+			}
+		}
+	}
+
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error": "NOT_FOUND"}`)
+	})
+	defer done()
+	table := client.Table("Tasks")
+	if err := table.Get("recGONE", &taskRecord{}); !errors.Is(err, airtable.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package airtable_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestArgErrors(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL)
+	})
+	defer done()
+	table := client.Table("Tasks")
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected a panic without ArgErrors")
+			}
+		}()
+		table.Create(&struct{ ID string }{})
+	}()
+
+	client.ArgErrors = true
+	var invalid airtable.ErrInvalidArg
+	if err := table.Create(&struct{ ID string }{}); !errors.As(err, &invalid) || invalid.Arg != "recordPtr" {
+		t.Errorf("expected an ErrInvalidArg for recordPtr, got %v", err)
+	}
+	if err := table.List([]taskRecord{}, nil); !errors.As(err, &invalid) || invalid.Arg != "listPtr" {
+		t.Errorf("expected an ErrInvalidArg for listPtr, got %v", err)
+	}
+	if err := table.Update(nil); !errors.As(err, &invalid) {
+		t.Errorf("expected an ErrInvalidArg for a nil record, got %v", err)
+	}
+}
+
+func TestArgErrorsInsteadOfPanics(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL)
+	})
+	defer done()
+	client.ArgErrors = true
+	table := client.Table("Tasks")
+	table.BindLink("Title", airtable.LinkBinding{Table: client.Table("People"), KeyField: "Name"})
+
+	type broken struct {
+		airtable.Record
+		Fields struct {
+			Ready chan bool
+		}
+	}
+	type linked struct {
+		airtable.Record
+		Fields struct {
+			Title string
+		}
+	}
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"sort by a missing field", func() error {
+			return table.List(&[]taskRecord{}, &airtable.Options{SortBy: []airtable.SortField{{Field: "Nope"}}})
+		}},
+		{"invalid sort direction", func() error {
+			return table.List(&[]taskRecord{}, &airtable.Options{Sort: airtable.Sort{{"Title", "sideways"}}})
+		}},
+		{"list a missing field", func() error {
+			return table.List(&[]taskRecord{}, &airtable.Options{Fields: []string{"Nope"}})
+		}},
+		{"no fields to search", func() error {
+			return table.Search("a", nil, &[]struct {
+				ID     string
+				Fields struct{}
+			}{}, airtable.SearchLocal)
+		}},
+		{"search a missing field", func() error {
+			return table.Search("a", []string{"Nope"}, &[]taskRecord{}, airtable.SearchServer)
+		}},
+		{"linked field that isn't a RecordLink", func() error {
+			return table.Create(&linked{})
+		}},
+		{"nil map", func() error {
+			return table.ListIntoMap(nil, nil)
+		}},
+		{"unencodable create", func() error {
+			return table.Create(&broken{})
+		}},
+		{"unencodable update", func() error {
+			record := broken{}
+			record.ID = "rec1"
+			return table.Update(&record)
+		}},
+		{"unencodable batch", func() error {
+			return table.CreateBatch(&[]broken{{}})
+		}},
+	}
+	for _, test := range tests {
+		var invalid airtable.ErrInvalidArg
+		if err := test.call(); !errors.As(err, &invalid) {
+			t.Errorf("%s: expected an ErrInvalidArg, got %v", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package airtable
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// AuditAction is what happened to a record in an AuditEntry.
+type AuditAction string
+
+// The actions recorded in an audit trail.
+const (
+	AuditCreate  AuditAction = "create"
+	AuditUpdate  AuditAction = "update"
+	AuditDestroy AuditAction = "destroy"
+)
+
+// AuditEntry is one change to one cell, or the destruction of a record.
+//
+// - Time: when the change was made.
+//
+// - Action: whether the record was created, updated or destroyed.
+//
+// - TableID, RecordID: the record that changed.
+//
+// - FieldID, FieldName: the field that changed. Empty when a record is
+// destroyed; FieldName is only set if the trail has a Schema.
+//
+// - Old, New: the cell's value before and after the change. Old is only
+// available when the webhook includes previous values, and New is
+// empty when the cell was cleared.
+//
+// - User: who made the change, if it was made by a user.
+//
+// - Source: where the change came from, such as "client" or
+// "publicApi".
+type AuditEntry struct {
+	Time      time.Time
+	Action    AuditAction
+	TableID   string
+	RecordID  string
+	FieldID   string
+	FieldName string
+	Old       json.RawMessage
+	New       json.RawMessage
+	User      *WebhookUser
+	Source    string
+}
+
+// AuditTrail turns webhook payloads into a per-record history of who
+// changed which field and when, e.g. for compliance reporting. Feed it
+// payloads as they're fetched with Add; the entries can then be read
+// per record or all together and stored wherever they're needed.
+//
+// If Schema is set, entries get the names of the fields as well as
+// their IDs.
+type AuditTrail struct {
+	Schema *BaseSchema
+
+	records map[string][]AuditEntry
+}
+
+// Add records the changes in the payloads.
+func (a *AuditTrail) Add(payloads ...WebhookPayload) {
+	if a.records == nil {
+		a.records = map[string][]AuditEntry{}
+	}
+	for _, p := range payloads {
+		base := AuditEntry{
+			Time:   p.Timestamp,
+			User:   p.ActionMetadata.SourceMetadata.User,
+			Source: p.ActionMetadata.Source,
+		}
+		for tableID, changes := range p.ChangedTablesByID {
+			base.TableID = tableID
+			for id, record := range changes.CreatedRecordsByID {
+				for fieldID, cell := range record.CellValuesByFieldID {
+					a.add(base, AuditCreate, id, fieldID, nil, cell)
+				}
+			}
+			for id, change := range changes.ChangedRecordsByID {
+				for fieldID, cell := range change.Current.CellValuesByFieldID {
+					var old json.RawMessage
+					if change.Previous != nil {
+						old = change.Previous.CellValuesByFieldID[fieldID]
+					}
+					a.add(base, AuditUpdate, id, fieldID, old, cell)
+				}
+				// cleared cells are only in the previous values.
+				if change.Previous != nil {
+					for fieldID, old := range change.Previous.CellValuesByFieldID {
+						if _, ok := change.Current.CellValuesByFieldID[fieldID]; !ok {
+							a.add(base, AuditUpdate, id, fieldID, old, nil)
+						}
+					}
+				}
+			}
+			for _, id := range changes.DestroyedRecordIDs {
+				a.add(base, AuditDestroy, id, "", nil, nil)
+			}
+		}
+	}
+}
+
+func (a *AuditTrail) add(entry AuditEntry, action AuditAction, recordID, fieldID string, before, after json.RawMessage) {
+	entry.Action = action
+	entry.RecordID = recordID
+	entry.FieldID = fieldID
+	entry.Old = before
+	entry.New = after
+	if a.Schema != nil && fieldID != "" {
+		if table := a.Schema.Table(entry.TableID); table != nil {
+			if field := table.Field(fieldID); field != nil {
+				entry.FieldName = field.Name
+			}
+		}
+	}
+	a.records[recordID] = append(a.records[recordID], entry)
+}
+
+// Record returns the history of the record, oldest first.
+func (a *AuditTrail) Record(id string) []AuditEntry {
+	entries := append([]AuditEntry(nil), a.records[id]...)
+	sortAuditEntries(entries)
+	return entries
+}
+
+// Entries returns the history of every record, oldest first.
+func (a *AuditTrail) Entries() []AuditEntry {
+	var entries []AuditEntry
+	for _, history := range a.records {
+		entries = append(entries, history...)
+	}
+	sortAuditEntries(entries)
+	return entries
+}
+
+// sortAuditEntries sorts entries by time, then by record and field so
+// entries for the same change come out in a stable order.
+func sortAuditEntries(entries []AuditEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if !a.Time.Equal(b.Time) {
+			return a.Time.Before(b.Time)
+		}
+		if a.RecordID != b.RecordID {
+			return a.RecordID < b.RecordID
+		}
+		return a.FieldID < b.FieldID
+	})
+}
@@ -0,0 +1,82 @@
+package airtable_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+const webhookPayloads = `{"cursor": 3, "mightHaveMore": false, "payloads": [
+	{
+		"timestamp": "2024-01-01T10:00:00.000Z",
+		"baseTransactionNumber": 1,
+		"actionMetadata": {"source": "client", "sourceMetadata": {"user": {"id": "usrA", "email": "a@example.com", "name": "A"}}},
+		"changedTablesById": {"tblBOOKS": {
+			"createdRecordsById": {"rec1": {"createdTime": "2024-01-01T10:00:00.000Z", "cellValuesByFieldId": {"fldTITLE": "Dune"}}}
+		}}
+	},
+	{
+		"timestamp": "2024-01-02T10:00:00.000Z",
+		"baseTransactionNumber": 2,
+		"actionMetadata": {"source": "publicApi", "sourceMetadata": {}},
+		"changedTablesById": {"tblBOOKS": {
+			"changedRecordsById": {"rec1": {
+				"current": {"cellValuesByFieldId": {"fldTITLE": "Dune Messiah"}},
+				"previous": {"cellValuesByFieldId": {"fldTITLE": "Dune", "fldPAGES": 412}}
+			}},
+			"destroyedRecordIds": ["rec2"]
+		}}
+	}
+]}`
+
+func TestAuditTrail(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v0/bases/appTEST/webhooks/achHOOK/payloads":
+			if got := r.URL.Query().Get("cursor"); got != "1" {
+				t.Errorf("unexpected cursor %s", got)
+			}
+			fmt.Fprint(w, webhookPayloads)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+	defer done()
+
+	payloads, next, more, err := client.WebhookPayloads(context.Background(), "achHOOK", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != 3 || more || len(payloads) != 2 {
+		t.Fatalf("unexpected response: %d payloads, cursor %d, more %t", len(payloads), next, more)
+	}
+
+	trail := airtable.AuditTrail{}
+	trail.Add(payloads...)
+
+	history := trail.Record("rec1")
+	if len(history) != 3 {
+		t.Fatalf("expected 3 entries for rec1, got %+v", history)
+	}
+	created, cleared, renamed := history[0], history[1], history[2]
+	if created.Action != airtable.AuditCreate || string(created.New) != `"Dune"` || created.User.Email != "a@example.com" {
+		t.Errorf("unexpected create entry %+v", created)
+	}
+	if cleared.Action != airtable.AuditUpdate || cleared.FieldID != "fldPAGES" || string(cleared.Old) != "412" || cleared.New != nil {
+		t.Errorf("unexpected entry for the cleared cell %+v", cleared)
+	}
+	if renamed.FieldID != "fldTITLE" || string(renamed.Old) != `"Dune"` || string(renamed.New) != `"Dune Messiah"` || renamed.Source != "publicApi" {
+		t.Errorf("unexpected update entry %+v", renamed)
+	}
+
+	destroyed := trail.Record("rec2")
+	if len(destroyed) != 1 || destroyed[0].Action != airtable.AuditDestroy {
+		t.Errorf("expected rec2 to be destroyed, got %+v", destroyed)
+	}
+	if len(trail.Entries()) != 4 {
+		t.Errorf("expected 4 entries in all, got %d", len(trail.Entries()))
+	}
+}
@@ -0,0 +1,268 @@
+package airtable
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// CreateBatch makes new records in the table from the slice of records
+// pointed to by recordsPtr, sending them 10 at a time, the most the API
+// accepts in one request. On success, the ID and CreatedTime of every
+// record are updated. Each request asks for typecast if any of its
+// records do.
+//
+// recordsPtr has the same requirements as listPtr in List. If a request
+// fails, CreateBatch stops and returns the error; the records created
+// before it have their IDs set.
+func (t *Table) CreateBatch(recordsPtr interface{}) error {
+	return t.CreateBatchContext(context.Background(), recordsPtr)
+}
+
+// CreateBatchContext is like CreateBatch but the requests are bound to
+// ctx.
+func (t *Table) CreateBatchContext(ctx context.Context, recordsPtr interface{}) error {
+	if err := t.argError(checkListArg(recordsPtr)); err != nil {
+		return err
+	}
+	records := reflect.ValueOf(recordsPtr).Elem()
+	return inBatches(records.Len(), func(start, end int) error {
+		return t.createBatch(ctx, records.Slice(start, end))
+	})
+}
+
+// createBatch creates up to maxBatchSize records in a single request.
+func (t *Table) createBatch(ctx context.Context, records reflect.Value) error {
+	creates, typecast, err := t.encodeBatch(ctx, records, "CreateBatch")
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(struct {
+		Records  []batchRecord `json:"records"`
+		Typecast bool          `json:"typecast"`
+	}{creates, typecast})
+	if err != nil {
+		return t.argError(jsonArgError("CreateBatch", err))
+	}
+	res, err := t.client.RequestWithBodyContext(ctx, "POST", t.makePath(""), Options{}, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	created := struct {
+		Records []json.RawMessage
+	}{}
+	if err := json.Unmarshal(res, &created); err != nil {
+		return fmt.Errorf("airtable: could not unpack create response %s", err)
+	}
+	if len(created.Records) != records.Len() {
+		return fmt.Errorf("airtable: sent %d records to create, got %d back", records.Len(), len(created.Records))
+	}
+	for i, raw := range created.Records {
+		if err := t.client.decodeRecord(raw, records.Index(i).Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateBatch sends the updated records in the slice pointed to by
+// recordsPtr to the table, 10 at a time, like Update does for a single
+// record. recordsPtr has the same requirements as listPtr in List. If a
+// request fails, UpdateBatch stops and returns the error.
+func (t *Table) UpdateBatch(recordsPtr interface{}) error {
+	return t.UpdateBatchContext(context.Background(), recordsPtr)
+}
+
+// UpdateBatchContext is like UpdateBatch but the requests are bound to
+// ctx.
+func (t *Table) UpdateBatchContext(ctx context.Context, recordsPtr interface{}) error {
+	if err := t.argError(checkListArg(recordsPtr)); err != nil {
+		return err
+	}
+	records := reflect.ValueOf(recordsPtr).Elem()
+	return inBatches(records.Len(), func(start, end int) error {
+		return t.updateBatch(ctx, records.Slice(start, end))
+	})
+}
+
+// updateBatch updates up to maxBatchSize records in a single request.
+func (t *Table) updateBatch(ctx context.Context, records reflect.Value) error {
+	updates, typecast, err := t.encodeBatch(ctx, records, "UpdateBatch")
+	if err != nil {
+		return err
+	}
+	ids := make([]string, len(updates))
+	for i := range updates {
+		ids[i] = updates[i].ID
+	}
+	b, err := json.Marshal(struct {
+		Records  []batchRecord `json:"records"`
+		Typecast bool          `json:"typecast"`
+	}{updates, typecast})
+	if err != nil {
+		return t.argError(jsonArgError("UpdateBatch", err))
+	}
+	_, err = t.client.RequestWithBodyContext(ctx, "PATCH", t.makePath(""), Options{}, bytes.NewReader(b))
+	t.invalidate(ids...)
+	return err
+}
+
+// batchRecord is a record in the body of a batch request.
+type batchRecord struct {
+	ID     string          `json:"id,omitempty"`
+	Fields json.RawMessage `json:"fields"`
+}
+
+// encodeBatch prepares records for a batch request the way Create and
+// Update prepare a single record, and reports whether any of them ask
+// for typecast. op names the method in errors.
+func (t *Table) encodeBatch(ctx context.Context, records reflect.Value, op string) ([]batchRecord, bool, error) {
+	batch := make([]batchRecord, records.Len())
+	typecast := false
+	for i := range batch {
+		recordPtr := records.Index(i).Addr().Interface()
+		if err := t.resolveLinks(ctx, recordPtr); err != nil {
+			return nil, false, err
+		}
+		if t.client.ValidateSelects {
+			if err := t.validateSelects(ctx, recordPtr); err != nil {
+				return nil, false, err
+			}
+		}
+		if t.client.ValidateFormats {
+			if err := validateFormats(recordPtr); err != nil {
+				return nil, false, err
+			}
+		}
+		// as in Create, only codec and range errors are returned as
+		// they are, since the others are programming errors.
+		b, err := t.client.encodeRecordFields(recordPtr)
+		if dataError(err) {
+			return nil, false, err
+		}
+		if err != nil {
+			return nil, false, t.argError(jsonArgError(op, err))
+		}
+		batch[i] = batchRecord{ID: getID(recordPtr), Fields: b}
+		typecast = typecast || t.typecastFor(recordPtr)
+	}
+	return batch, typecast, nil
+}
+
+// inBatches calls fn with the bounds of each batch of n items the API
+// accepts in one request, stopping at the first error.
+func inBatches(n int, fn func(start, end int) error) error {
+	for start := 0; start < n; start += maxBatchSize {
+		end := start + maxBatchSize
+		if end > n {
+			end = n
+		}
+		if err := fn(start, end); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteBatch deletes the records with the given IDs, 10 per request,
+// and returns whether each one was deleted, in the same order. If a
+// request fails, DeleteBatch stops and returns the results so far along
+// with the error.
+func (t *Table) DeleteBatch(ids []string) ([]DeleteResult, error) {
+	return t.DeleteBatchContext(context.Background(), ids)
+}
+
+// DeleteBatchContext is like DeleteBatch but the requests are bound to
+// ctx.
+func (t *Table) DeleteBatchContext(ctx context.Context, ids []string) ([]DeleteResult, error) {
+	var results []DeleteResult
+	err := inBatches(len(ids), func(start, end int) error {
+		batch, err := t.deleteIDs(ctx, ids[start:end])
+		results = append(results, batch...)
+		return err
+	})
+	return results, err
+}
+
+// DeleteRecords is like DeleteBatch but deletes the records in the
+// slice pointed to by recordsPtr, which has the same requirements as
+// listPtr in List. The ID and CreatedTime of each record that was
+// deleted are removed, as in Delete, even if a later request fails.
+func (t *Table) DeleteRecords(recordsPtr interface{}) ([]DeleteResult, error) {
+	return t.DeleteRecordsContext(context.Background(), recordsPtr)
+}
+
+// DeleteRecordsContext is like DeleteRecords but the request is bound
+// to ctx.
+func (t *Table) DeleteRecordsContext(ctx context.Context, recordsPtr interface{}) ([]DeleteResult, error) {
+	if err := t.argError(checkListArg(recordsPtr)); err != nil {
+		return nil, err
+	}
+	records := reflect.ValueOf(recordsPtr).Elem()
+	ids := make([]string, records.Len())
+	for i := range ids {
+		ids[i] = records.Index(i).FieldByName("ID").String()
+	}
+	results, err := t.DeleteBatchContext(ctx, ids)
+	deleted := make(map[string]bool, len(results))
+	for _, result := range results {
+		deleted[result.ID] = result.Deleted
+	}
+	for i, id := range ids {
+		if deleted[id] {
+			markAsDeleted(records.Index(i).Addr().Interface())
+		}
+	}
+	return results, err
+}
+
+// GetMany looks up the records with the given IDs and appends them to
+// the slice pointed to by listPtr, in the same order as ids. The records
+// are requested with a RECORD_ID() formula in batches, so there are far
+// fewer requests than calling Get for each one, and records in the
+// client's RecordCache aren't requested at all. IDs of records that
+// don't exist are skipped, as are repeated IDs.
+//
+// listPtr has the same requirements as in List.
+func (t *Table) GetMany(ids []string, listPtr interface{}) error {
+	return t.GetManyContext(context.Background(), ids, listPtr)
+}
+
+// GetManyContext is like GetMany but the requests are bound to ctx.
+func (t *Table) GetManyContext(ctx context.Context, ids []string, listPtr interface{}) error {
+	if err := t.argError(checkListArg(listPtr)); err != nil {
+		return err
+	}
+
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	raw, err := t.rawRecords(ctx, unique, t.client.RecordCache)
+	if err != nil {
+		return err
+	}
+
+	list := reflect.ValueOf(listPtr).Elem()
+	typ := getRecordType(listPtr)
+	growList(list, len(raw), false)
+	for _, id := range unique {
+		data, ok := raw[id]
+		if !ok {
+			continue
+		}
+		record := reflect.New(typ)
+		if err := t.client.decodeRecord(data, record.Interface()); err != nil {
+			return err
+		}
+		list.Set(reflect.Append(list, record.Elem()))
+	}
+	return nil
+}
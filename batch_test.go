@@ -0,0 +1,179 @@
+package airtable_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestCreateBatch(t *testing.T) {
+	requests := 0
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body := struct {
+			Records []struct {
+				Fields struct{ Title string }
+			}
+			Typecast bool
+		}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.Method != "POST" || body.Typecast {
+			t.Errorf("unexpected request %s %+v", r.Method, body)
+		}
+		var records []string
+		for i, record := range body.Records {
+			records = append(records, fmt.Sprintf(`{"id": "rec%d", "createdTime": "2020-01-02T03:04:05.000Z", "fields": {"Title": %q}}`, i, record.Fields.Title))
+		}
+		fmt.Fprintf(w, `{"records": [%s]}`, strings.Join(records, ","))
+	})
+	defer done()
+
+	tasks := make([]taskRecord, 3)
+	for i := range tasks {
+		tasks[i].Fields.Title = fmt.Sprintf("task %d", i)
+	}
+	table := client.Table("Tasks")
+	if err := table.CreateBatch(&tasks); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("expected a single request, got %d", requests)
+	}
+	for i, task := range tasks {
+		if task.ID != fmt.Sprintf("rec%d", i) || task.CreatedTime.IsZero() || task.Fields.Title != fmt.Sprintf("task %d", i) {
+			t.Errorf("unexpected record %+v", task)
+		}
+	}
+
+	many := make([]taskRecord, 23)
+	if err := table.CreateBatch(&many); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 4 {
+		t.Errorf("expected 3 more requests, got %d", requests-1)
+	}
+	if many[22].ID != "rec2" || many[10].ID != "rec0" {
+		t.Errorf("expected every batch to be decoded, got %+v and %+v", many[10], many[22])
+	}
+}
+
+func TestUpdateBatch(t *testing.T) {
+	var batches [][]string
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		body := struct {
+			Records []struct {
+				ID     string
+				Fields struct{ Title string }
+			}
+		}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.Method != "PATCH" {
+			t.Errorf("unexpected method %s", r.Method)
+		}
+		var ids []string
+		for _, record := range body.Records {
+			ids = append(ids, record.ID)
+			if record.Fields.Title != "updated" {
+				t.Errorf("unexpected record %+v", record)
+			}
+		}
+		batches = append(batches, ids)
+		fmt.Fprint(w, `{"records": []}`)
+	})
+	defer done()
+
+	tasks := make([]taskRecord, 12)
+	for i := range tasks {
+		tasks[i].ID = fmt.Sprintf("rec%d", i)
+		tasks[i].Fields.Title = "updated"
+	}
+	table := client.Table("Tasks")
+	if err := table.UpdateBatch(&tasks); err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 2 || len(batches[0]) != 10 || batches[1][1] != "rec11" {
+		t.Errorf("unexpected batches %v", batches)
+	}
+}
+
+func TestDeleteBatch(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		ids := r.URL.Query()["records[]"]
+		if r.Method != "DELETE" || len(ids) > 10 {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		var results []string
+		for _, id := range ids {
+			results = append(results, fmt.Sprintf(`{"id": %q, "deleted": %t}`, id, id != "rec2"))
+		}
+		fmt.Fprintf(w, `{"records": [%s]}`, strings.Join(results, ","))
+	})
+	defer done()
+
+	table := client.Table("Tasks")
+	results, err := table.DeleteBatch([]string{"rec1", "rec2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || !results[0].Deleted || results[0].ID != "rec1" || results[1].Deleted {
+		t.Errorf("unexpected results %+v", results)
+	}
+
+	tasks := make([]taskRecord, 2)
+	tasks[0].ID, tasks[1].ID = "rec1", "rec2"
+	if _, err := table.DeleteRecords(&tasks); err != nil {
+		t.Fatal(err)
+	}
+	if tasks[0].ID != "" || tasks[1].ID != "rec2" {
+		t.Errorf("expected only the deleted record to be marked, got %+v", tasks)
+	}
+
+	ids := make([]string, 15)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("rec%d", i+10)
+	}
+	results, err = table.DeleteBatch(ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 15 || results[14].ID != "rec24" || !results[14].Deleted {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
+
+func TestGetMany(t *testing.T) {
+	requests := 0
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		formula := r.URL.Query().Get("filterByFormula")
+		var records []string
+		for _, id := range []string{"rec2", "rec1"} {
+			if strings.Contains(formula, id) {
+				records = append(records, fmt.Sprintf(`{"id": %q, "fields": {"Title": %q}}`, id, id))
+			}
+		}
+		fmt.Fprintf(w, `{"records": [%s]}`, strings.Join(records, ","))
+	})
+	defer done()
+	client.RecordCache = airtable.NewRecordCache(10, 0)
+
+	table := client.Table("Tasks")
+	tasks := []taskRecord{}
+	if err := table.GetMany([]string{"rec1", "recGONE", "rec2", "rec1"}, &tasks); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 || len(tasks) != 2 || tasks[0].ID != "rec1" || tasks[1].Fields.Title != "rec2" {
+		t.Errorf("unexpected records %+v after %d requests", tasks, requests)
+	}
+
+	if err := table.GetMany([]string{"rec2"}, &tasks); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 || len(tasks) != 3 {
+		t.Errorf("expected cached record to be appended without a request, got %d records after %d requests", len(tasks), requests)
+	}
+}
@@ -0,0 +1,286 @@
+package airtable
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// maxBatchSize is the most records the API accepts in one create,
+// update or delete request.
+const maxBatchSize = 10
+
+// BulkOptions controls bulk operations like DeleteWhere.
+//
+// - DryRun: find the matching records and report how many there are,
+// but don't change them.
+//
+// - Progress: called after each batch with the number of records
+// processed so far and the total number of matching records.
+type BulkOptions struct {
+	DryRun   bool
+	Progress func(done, total int)
+}
+
+// DeleteWhere deletes every record in the table matching the formula,
+// in batches of 10, and returns the IDs of the records deleted. With
+// DryRun set, it returns the IDs of the records that would have been
+// deleted instead. options may be nil. Like Count, it only asks for one
+// small field of each record when listing them if it can.
+//
+// If a batch fails, DeleteWhere stops and returns the IDs of the records
+// deleted before the failure along with the error.
+func (t *Table) DeleteWhere(formula string, options *BulkOptions) ([]string, error) {
+	return t.DeleteWhereContext(context.Background(), formula, options)
+}
+
+// DeleteWhereContext is like DeleteWhere but the requests are bound to
+// ctx.
+func (t *Table) DeleteWhereContext(ctx context.Context, formula string, options *BulkOptions) ([]string, error) {
+	if options == nil {
+		options = &BulkOptions{}
+	}
+
+	ids, err := t.listIDs(ctx, formula, t.smallFields(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+	if options.DryRun {
+		return ids, nil
+	}
+
+	var deleted []string
+	err = inBatches(len(ids), func(start, end int) error {
+		results, err := t.deleteIDs(ctx, ids[start:end])
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			if result.Deleted {
+				deleted = append(deleted, result.ID)
+			}
+		}
+		if options.Progress != nil {
+			options.Progress(len(deleted), len(ids))
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+// UpdateWhere applies the same fields to every record in the table
+// matching the formula, in batches of 10, and returns the updated
+// records with their fields as Airtable sent them back, which tells
+// which records were changed and what they hold now. The keys of fields
+// are Airtable field names, and fields not in the map are left as they
+// are. With DryRun set, it returns the records that would have been
+// updated, with only their IDs set, instead. options may be nil. The
+// matching records are listed as in DeleteWhere.
+//
+// If a batch fails, UpdateWhere stops and returns the records updated
+// before the failure along with the error.
+func (t *Table) UpdateWhere(formula string, fields Fields, options *BulkOptions) ([]UntypedRecord, error) {
+	return t.UpdateWhereContext(context.Background(), formula, fields, options)
+}
+
+// UpdateWhereContext is like UpdateWhere but the requests are bound to
+// ctx.
+func (t *Table) UpdateWhereContext(ctx context.Context, formula string, fields Fields, options *BulkOptions) ([]UntypedRecord, error) {
+	if options == nil {
+		options = &BulkOptions{}
+	}
+
+	if err := t.validateFieldSelects(ctx, fields); err != nil {
+		return nil, err
+	}
+	ids, err := t.listIDs(ctx, formula, t.smallFields(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+	if options.DryRun {
+		records := make([]UntypedRecord, len(ids))
+		for i, id := range ids {
+			records[i].ID = id
+		}
+		return records, nil
+	}
+
+	var updated []UntypedRecord
+	err = inBatches(len(ids), func(start, end int) error {
+		records, err := t.patchIDs(ctx, ids[start:end], fields)
+		if err != nil {
+			return err
+		}
+		updated = append(updated, records...)
+		if options.Progress != nil {
+			options.Progress(len(updated), len(ids))
+		}
+		return nil
+	})
+	return updated, err
+}
+
+// Count returns the number of records in the table that match the
+// formula; an empty formula matches every record. The API can't count
+// records, so Count lists them, asking for only one small field if the
+// table's schema can be read (see Table.Describe) and for whole records
+// otherwise.
+func (t *Table) Count(formula string) (int, error) {
+	return t.CountContext(context.Background(), formula)
+}
+
+// CountContext is like Count but the requests are bound to ctx.
+func (t *Table) CountContext(ctx context.Context, formula string) (int, error) {
+	ids, err := t.listIDs(ctx, formula, t.smallFields(ctx)...)
+	return len(ids), err
+}
+
+// Truncate deletes every record in the table, 10 per request, and
+// returns the number of records deleted. Like Count, it only asks for
+// one small field of each record when listing them if it can. If a
+// request fails, Truncate stops and returns the number of records
+// deleted before it along with the error.
+func (t *Table) Truncate() (int, error) {
+	return t.TruncateContext(context.Background())
+}
+
+// TruncateContext is like Truncate but the requests are bound to ctx.
+func (t *Table) TruncateContext(ctx context.Context) (int, error) {
+	ids, err := t.listIDs(ctx, "", t.smallFields(ctx)...)
+	if err != nil {
+		return 0, err
+	}
+	results, err := t.DeleteBatchContext(ctx, ids)
+	deleted := 0
+	for _, result := range results {
+		if result.Deleted {
+			deleted++
+		}
+	}
+	return deleted, err
+}
+
+// smallFields returns the fields to request when only the records' IDs
+// are needed: a small field if the schema can be read, or none, meaning
+// every field, if it can't.
+func (t *Table) smallFields(ctx context.Context) []string {
+	if field := t.smallField(ctx); field != "" {
+		return []string{field}
+	}
+	return nil
+}
+
+// smallFieldTypes are the types of fields whose values are small,
+// preferred in order by smallField.
+var smallFieldTypes = []string{"checkbox", "number", "rating", "percent", "currency", "autoNumber"}
+
+// smallField returns the ID of a field in the table whose values are
+// small, or of the primary field if there's none, so listings that only
+// need the records' IDs don't download whole records. It returns "" if
+// the schema can't be read.
+func (t *Table) smallField(ctx context.Context) string {
+	table, err := t.DescribeContext(ctx)
+	if err != nil {
+		return ""
+	}
+	for _, typ := range smallFieldTypes {
+		for _, field := range table.Fields {
+			if field.Type == typ {
+				return field.ID
+			}
+		}
+	}
+	return table.PrimaryFieldID
+}
+
+// listIDs returns the IDs of all records in the table that match the
+// formula. An empty formula matches every record. If fields are given,
+// only those fields are requested.
+func (t *Table) listIDs(ctx context.Context, formula string, fields ...string) ([]string, error) {
+	query := url.Values{}
+	if formula != "" {
+		query.Set("filterByFormula", formula)
+	}
+	if len(fields) > 0 {
+		query["fields[]"] = fields
+	}
+	var ids []string
+	for {
+		bytes, err := t.client.RequestContext(ctx, "GET", t.makePath(""), query)
+		if err != nil {
+			return nil, err
+		}
+		page := struct {
+			Records []Record
+			Offset  string
+		}{}
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return nil, err
+		}
+		for _, record := range page.Records {
+			ids = append(ids, record.ID)
+		}
+		if page.Offset == "" {
+			break
+		}
+		query.Set("offset", page.Offset)
+	}
+	return ids, nil
+}
+
+// deleteIDs deletes up to maxBatchSize records in a single request.
+func (t *Table) deleteIDs(ctx context.Context, ids []string) ([]DeleteResult, error) {
+	query := url.Values{"records[]": ids}
+	res, err := t.client.RequestContext(ctx, "DELETE", t.makePath(""), query)
+	t.invalidate(ids...)
+	if err != nil {
+		return nil, err
+	}
+	deleted := struct {
+		Records []DeleteResult
+	}{}
+	if err := json.Unmarshal(res, &deleted); err != nil {
+		return nil, fmt.Errorf("airtable: could not unpack delete response %s", err)
+	}
+	return deleted.Records, nil
+}
+
+// patchIDs applies fields to up to maxBatchSize records in a single
+// request and returns the updated records.
+func (t *Table) patchIDs(ctx context.Context, ids []string, fields Fields) ([]UntypedRecord, error) {
+	type update struct {
+		ID     string `json:"id"`
+		Fields Fields `json:"fields"`
+	}
+	updates := make([]update, len(ids))
+	for i, id := range ids {
+		updates[i] = update{ID: id, Fields: fields}
+	}
+	b, err := json.Marshal(struct {
+		Records  []update `json:"records"`
+		Typecast bool     `json:"typecast,omitempty"`
+	}{updates, t.typecast})
+	if err != nil {
+		return nil, err
+	}
+	res, err := t.client.RequestWithBodyContext(ctx, "PATCH", t.makePath(""), Options{}, bytes.NewReader(b))
+	t.invalidate(ids...)
+	if err != nil {
+		return nil, err
+	}
+	updated := struct {
+		Records []json.RawMessage
+	}{}
+	if err := json.Unmarshal(res, &updated); err != nil {
+		return nil, fmt.Errorf("airtable: could not unpack update response %s", err)
+	}
+	records := make([]UntypedRecord, len(updated.Records))
+	for i, raw := range updated.Records {
+		if err := t.client.decodeRecord(raw, &records[i]); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,266 @@
+package airtable_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+// recordsResponse makes a list response with n records, whose IDs are
+// numbered starting at start.
+func recordsResponse(start, n int, offset string) string {
+	records := make([]string, n)
+	for i := range records {
+		records[i] = fmt.Sprintf(`{"id": "rec%d", "fields": {}}`, start+i)
+	}
+	return fmt.Sprintf(`{"offset": %q, "records": [%s]}`, offset, strings.Join(records, ","))
+}
+
+func TestDeleteWhere(t *testing.T) {
+	var deleted [][]string
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/meta/") {
+			fmt.Fprint(w, schemaResponse)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			if got := r.URL.Query().Get("filterByFormula"); got != "{Stale}" {
+				t.Errorf("unexpected formula %s", got)
+			}
+			if fields := r.URL.Query()["fields[]"]; len(fields) != 1 || fields[0] != "fldTITLE" {
+				t.Errorf("expected only the primary field to be listed, got %v", fields)
+			}
+			if r.URL.Query().Get("offset") == "" {
+				fmt.Fprint(w, recordsResponse(0, 15, "page2"))
+				return
+			}
+			fmt.Fprint(w, recordsResponse(15, 8, ""))
+		case "DELETE":
+			ids := r.URL.Query()["records[]"]
+			deleted = append(deleted, ids)
+			var records []string
+			for _, id := range ids {
+				records = append(records, fmt.Sprintf(`{"id": %q, "deleted": true}`, id))
+			}
+			fmt.Fprintf(w, `{"records": [%s]}`, strings.Join(records, ","))
+		}
+	})
+	defer done()
+
+	table := client.Table("Books")
+
+	ids, err := table.DeleteWhere("{Stale}", &airtable.BulkOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 23 || len(deleted) != 0 {
+		t.Fatalf("expected dry run to find 23 and delete nothing, got %v, %v", ids, deleted)
+	}
+
+	var progress []string
+	ids, err = table.DeleteWhere("{Stale}", &airtable.BulkOptions{
+		Progress: func(done, total int) {
+			progress = append(progress, fmt.Sprintf("%d/%d", done, total))
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 23 || ids[0] != "rec0" || ids[22] != "rec22" {
+		t.Errorf("expected 23 deleted, got %v", ids)
+	}
+	if len(deleted) != 3 || len(deleted[0]) != 10 || len(deleted[2]) != 3 {
+		t.Errorf("expected batches of 10, got %v", deleted)
+	}
+	if got := fmt.Sprint(progress); got != "[10/23 20/23 23/23]" {
+		t.Errorf("unexpected progress %s", got)
+	}
+}
+
+func TestDeleteWhereContext(t *testing.T) {
+	var deletes int
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/meta/"):
+			fmt.Fprint(w, schemaResponse)
+		case r.Method == "GET":
+			fmt.Fprint(w, recordsResponse(0, 23, ""))
+		case r.Method == "DELETE":
+			deletes++
+			var results []string
+			for _, id := range r.URL.Query()["records[]"] {
+				results = append(results, fmt.Sprintf(`{"id": %q, "deleted": true}`, id))
+			}
+			fmt.Fprintf(w, `{"records": [%s]}`, strings.Join(results, ","))
+		}
+	})
+	defer done()
+
+	// canceling after the first batch stops the rest.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	table := client.Table("Books")
+	ids, err := table.DeleteWhereContext(ctx, "{Year} < 1900", &airtable.BulkOptions{
+		Progress: func(done, total int) { cancel() },
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the context's error, got %v", err)
+	}
+	if len(ids) != 10 || deletes != 1 {
+		t.Errorf("expected only the first batch to be deleted, got %d in %d requests", len(ids), deletes)
+	}
+}
+
+func TestUpdateWhere(t *testing.T) {
+	var patched int
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, recordsResponse(0, 12, ""))
+		case "PATCH":
+			body := struct {
+				Records []struct {
+					ID     string
+					Fields map[string]interface{}
+				}
+				Typecast bool
+			}{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+				return
+			}
+			if !body.Typecast {
+				t.Error("expected typecast to be sent")
+			}
+			for _, record := range body.Records {
+				if record.Fields["Status"] != "Archived" || len(record.Fields) != 1 {
+					t.Errorf("unexpected fields %v", record.Fields)
+				}
+			}
+			patched += len(body.Records)
+			b, _ := json.Marshal(body)
+			w.Write(b)
+		}
+	})
+	defer done()
+
+	table := client.Table("Books")
+	table.SetTypecast(true)
+	records, err := table.UpdateWhere("{Year} < 1900", airtable.Fields{"Status": "Archived"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 12 || patched != 12 {
+		t.Errorf("expected 12 updated, got %d (%d patched)", len(records), patched)
+	}
+	if records[11].ID == "" || records[11].Fields["Status"] != "Archived" {
+		t.Errorf("expected the updated records to be returned, got %+v", records[11])
+	}
+}
+
+func TestUpdateWhereContext(t *testing.T) {
+	var patches int
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/meta/"):
+			fmt.Fprint(w, schemaResponse)
+		case r.Method == "GET":
+			fmt.Fprint(w, recordsResponse(0, 12, ""))
+		case r.Method == "PATCH":
+			patches++
+			b, _ := ioutil.ReadAll(r.Body)
+			w.Write(b)
+		}
+	})
+	defer done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	table := client.Table("Books")
+	records, err := table.UpdateWhereContext(ctx, "{Year} < 1900", airtable.Fields{"Status": "Archived"}, &airtable.BulkOptions{
+		Progress: func(done, total int) { cancel() },
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the context's error, got %v", err)
+	}
+	if len(records) != 10 || patches != 1 {
+		t.Errorf("expected only the first batch to be updated, got %d in %d requests", len(records), patches)
+	}
+}
+
+func TestCount(t *testing.T) {
+	describable := true
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/meta/") {
+			if !describable {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"error": {"type": "INVALID_PERMISSIONS"}}`)
+				return
+			}
+			fmt.Fprint(w, schemaResponse)
+			return
+		}
+		fields := r.URL.Query()["fields[]"]
+		if describable && (len(fields) != 1 || fields[0] != "fldTITLE") || !describable && len(fields) != 0 {
+			t.Errorf("unexpected fields %v", fields)
+		}
+		if r.URL.Query().Get("offset") == "" {
+			fmt.Fprint(w, recordsResponse(0, 100, "page2"))
+			return
+		}
+		fmt.Fprint(w, recordsResponse(100, 7, ""))
+	})
+	defer done()
+
+	table := client.Table("Books")
+	if n, err := table.Count("{Done}"); err != nil || n != 107 {
+		t.Errorf("expected 107 records, got %d (%v)", n, err)
+	}
+
+	describable = false
+	client.InvalidateSchema()
+	if n, err := table.Count(""); err != nil || n != 107 {
+		t.Errorf("expected 107 records without the schema, got %d (%v)", n, err)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	var deleted int
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/meta/"):
+			fmt.Fprint(w, schemaResponse)
+		case r.Method == "GET":
+			if fields := r.URL.Query()["fields[]"]; len(fields) != 1 {
+				t.Errorf("expected a single field to be requested, got %v", fields)
+			}
+			fmt.Fprint(w, recordsResponse(0, 25, ""))
+		case r.Method == "DELETE":
+			ids := r.URL.Query()["records[]"]
+			deleted += len(ids)
+			var results []string
+			for _, id := range ids {
+				results = append(results, fmt.Sprintf(`{"id": %q, "deleted": true}`, id))
+			}
+			fmt.Fprintf(w, `{"records": [%s]}`, strings.Join(results, ","))
+		}
+	})
+	defer done()
+
+	table := client.Table("Books")
+	n, err := table.Truncate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 25 || deleted != 25 {
+		t.Errorf("expected 25 records to be deleted, got %d (%d)", n, deleted)
+	}
+}
@@ -0,0 +1,191 @@
+package airtable
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// RecordCache is a least-recently-used cache of records by ID, shared
+// by the tables of the clients that use it. When a client has one, Get
+// and LinkedRecords.Fetch use records from the cache instead of fetching
+// them again, which helps jobs that resolve the same linked records many
+// times. Records updated or deleted through the client are dropped from
+// the cache; changes made elsewhere are seen once the entries expire or
+// are invalidated.
+type RecordCache struct {
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type cacheEntry struct {
+	key     string
+	raw     json.RawMessage
+	expires time.Time
+}
+
+// NewRecordCache makes a cache holding up to size records, each for at
+// most ttl. If ttl is 0, records are kept until they're pushed out or
+// invalidated.
+func NewRecordCache(size int, ttl time.Duration) *RecordCache {
+	return &RecordCache{
+		size:    size,
+		ttl:     ttl,
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+	}
+}
+
+// Invalidate drops the record with the given ID in the table from the
+// cache.
+func (c *RecordCache) Invalidate(table, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[cacheKey(table, id)]; ok {
+		c.remove(e)
+	}
+}
+
+// Purge drops every record from the cache.
+func (c *RecordCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]*list.Element{}
+	c.lru.Init()
+}
+
+func (c *RecordCache) get(table, id string) (json.RawMessage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[cacheKey(table, id)]
+	if !ok {
+		return nil, false
+	}
+	entry := e.Value.(*cacheEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.remove(e)
+		return nil, false
+	}
+	c.lru.MoveToFront(e)
+	return entry.raw, true
+}
+
+func (c *RecordCache) put(table, id string, raw json.RawMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := cacheKey(table, id)
+	var expires time.Time
+	if c.ttl > 0 {
+		expires = time.Now().Add(c.ttl)
+	}
+	if e, ok := c.entries[key]; ok {
+		e.Value = &cacheEntry{key: key, raw: raw, expires: expires}
+		c.lru.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, raw: raw, expires: expires})
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *RecordCache) remove(e *list.Element) {
+	c.lru.Remove(e)
+	delete(c.entries, e.Value.(*cacheEntry).key)
+}
+
+func cacheKey(table, id string) string {
+	return table + "/" + id
+}
+
+// DefaultLinkCacheSize and DefaultLinkCacheTTL are the size and TTL of
+// the cache Link.Load uses for clients without a RecordCache.
+var (
+	DefaultLinkCacheSize = 10000
+	DefaultLinkCacheTTL  = time.Minute
+)
+
+// invalidate drops records from the client's caches.
+func (t *Table) invalidate(ids ...string) {
+	for _, cache := range []*RecordCache{t.client.RecordCache, t.client.linkCache} {
+		if cache == nil {
+			continue
+		}
+		for _, id := range ids {
+			cache.Invalidate(t.name, id)
+		}
+	}
+}
+
+// loadCache returns the cache Link.Load uses: the client's RecordCache,
+// or else a short-lived cache of its own, so records linked from many
+// records of a listing are fetched once.
+func (c *Client) loadCache() *RecordCache {
+	if c.RecordCache != nil {
+		return c.RecordCache
+	}
+	setupMu.Lock()
+	defer setupMu.Unlock()
+	if c.linkCache == nil {
+		c.linkCache = NewRecordCache(DefaultLinkCacheSize, DefaultLinkCacheTTL)
+	}
+	return c.linkCache
+}
+
+// rawRecords returns the JSON of the records with the given IDs, taking
+// them from cache, if it isn't nil, when it has them and fetching the
+// rest in batches. Records that don't exist are left out.
+func (t *Table) rawRecords(ctx context.Context, ids []string, cache *RecordCache) (map[string]json.RawMessage, error) {
+	records := make(map[string]json.RawMessage, len(ids))
+	var missing []string
+	for _, id := range ids {
+		if cache != nil {
+			if raw, ok := cache.get(t.name, id); ok {
+				records[id] = raw
+				continue
+			}
+		}
+		missing = append(missing, id)
+	}
+
+	for start := 0; start < len(missing); start += linkBatchSize {
+		end := start + linkBatchSize
+		if end > len(missing) {
+			end = len(missing)
+		}
+		options := &Options{Filter: recordIDFormula(missing[start:end])}
+		for {
+			bytes, err := readAll(t.client.requestPage(ctx, t.makePath(""), options))
+			if err != nil {
+				return nil, err
+			}
+			page := struct {
+				Records []json.RawMessage
+				Offset  string
+			}{}
+			if err := json.Unmarshal(bytes, &page); err != nil {
+				return nil, err
+			}
+			for _, raw := range page.Records {
+				record := Record{}
+				if err := json.Unmarshal(raw, &record); err != nil {
+					return nil, err
+				}
+				records[record.ID] = raw
+				if cache != nil {
+					cache.put(t.name, record.ID, raw)
+				}
+			}
+			if options.Offset = page.Offset; options.Offset == "" {
+				break
+			}
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,85 @@
+package airtable_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestRecordCache(t *testing.T) {
+	requests := map[string]int{}
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/v0/appTEST/Tasks/")
+		requests[r.Method+" "+id]++
+		fmt.Fprintf(w, `{"id": %q, "fields": {"Title": "Write tests"}}`, id)
+	})
+	defer done()
+	client.RecordCache = airtable.NewRecordCache(1, 0)
+
+	table := client.Table("Tasks")
+	get := func(id string) {
+		t.Helper()
+		record := taskRecord{}
+		if err := table.Get(id, &record); err != nil {
+			t.Fatal(err)
+		}
+		if record.ID != id || record.Fields.Title != "Write tests" {
+			t.Errorf("unexpected record %+v", record)
+		}
+	}
+
+	get("rec1")
+	get("rec1")
+	if requests["GET rec1"] != 1 {
+		t.Errorf("expected rec1 to be cached, got %d requests", requests["GET rec1"])
+	}
+
+	// the cache only holds one record, so rec2 pushes rec1 out.
+	get("rec2")
+	get("rec1")
+	if requests["GET rec1"] != 2 {
+		t.Errorf("expected rec1 to be pushed out, got %d requests", requests["GET rec1"])
+	}
+
+	// updating a record drops it from the cache.
+	record := taskRecord{}
+	record.ID = "rec1"
+	if err := table.Update(&record); err != nil {
+		t.Fatal(err)
+	}
+	get("rec1")
+	if requests["GET rec1"] != 3 {
+		t.Errorf("expected rec1 to be invalidated by Update, got %d requests", requests["GET rec1"])
+	}
+
+	client.RecordCache.Invalidate("Tasks", "rec1")
+	get("rec1")
+	if requests["GET rec1"] != 4 {
+		t.Errorf("expected rec1 to be invalidated, got %d requests", requests["GET rec1"])
+	}
+}
+
+func TestRecordCacheTTL(t *testing.T) {
+	requests := 0
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"id": "rec1", "fields": {}}`)
+	})
+	defer done()
+	client.RecordCache = airtable.NewRecordCache(10, time.Millisecond)
+
+	table := client.Table("Tasks")
+	for i := 0; i < 2; i++ {
+		if err := table.Get("rec1", &taskRecord{}); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if requests != 2 {
+		t.Errorf("expected the record to expire, got %d requests", requests)
+	}
+}
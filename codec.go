@@ -0,0 +1,368 @@
+package airtable
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Codec converts between a Go type and the JSON value Airtable uses
+// for a cell. Codecs are registered with RegisterCodec and consulted
+// whenever a record whose Fields struct contains a field of the
+// registered type is encoded or decoded.
+//
+// Either function may be nil, which covers the asymmetric cases that
+// implementing json.Marshaler and json.Unmarshaler can't express:
+//
+// - Marshal == nil: the type is read-only (e.g. a computed column) and
+// the field is left out of Create and Update requests.
+//
+// - Unmarshal == nil: the type is write-only and the field is left
+// untouched when decoding a response.
+type Codec struct {
+	// Marshal receives the field value and returns the value to send
+	// for the cell, which must be marshalable by encoding/json. If it
+	// returns nil the field is omitted from the request.
+	Marshal func(v interface{}) (interface{}, error)
+
+	// Unmarshal receives the raw JSON for the cell and a pointer to the
+	// field it should be decoded into. It is not called for cells that
+	// are missing from the response.
+	Unmarshal func(data []byte, v interface{}) error
+}
+
+var codecRegistry = struct {
+	sync.RWMutex
+	codecs map[reflect.Type]Codec
+}{codecs: map[reflect.Type]Codec{}}
+
+// RegisterCodec registers codec for the type of value, replacing any
+// codec previously registered for that type. It's meant to be called
+// during initialization, for example:
+//
+//	airtable.RegisterCodec(Money{}, airtable.Codec{
+//		Marshal: func(v interface{}) (interface{}, error) {
+//			return v.(Money).Dollars(), nil
+//		},
+//		Unmarshal: func(data []byte, v interface{}) error {
+//			var dollars float64
+//			if err := json.Unmarshal(data, &dollars); err != nil {
+//				return err
+//			}
+//			*v.(*Money) = MoneyFromDollars(dollars)
+//			return nil
+//		},
+//	})
+//
+// RegisterCodec panics if value is nil or if both codec functions are
+// nil.
+func RegisterCodec(value interface{}, codec Codec) {
+	typ := reflect.TypeOf(value)
+	if typ == nil {
+		panic("airtable.RegisterCodec: value must not be nil")
+	}
+	if codec.Marshal == nil && codec.Unmarshal == nil {
+		panic(fmt.Sprintf("airtable.RegisterCodec: codec for %s has neither Marshal nor Unmarshal", typ))
+	}
+	codecRegistry.Lock()
+	codecRegistry.codecs[typ] = codec
+	codecRegistry.Unlock()
+}
+
+func lookupCodec(typ reflect.Type) (Codec, bool) {
+	codecRegistry.RLock()
+	codec, ok := codecRegistry.codecs[typ]
+	codecRegistry.RUnlock()
+	return codec, ok
+}
+
+// ErrCodec is returned when a registered codec fails to encode or
+// decode a field.
+type ErrCodec struct {
+	Field string
+	Type  reflect.Type
+	Err   error
+}
+
+func (e ErrCodec) Error() string {
+	return fmt.Sprintf("airtable codec error: field %s (%s): %s", e.Field, e.Type, e.Err)
+}
+
+// dataError reports whether err, from encoding a record, was caused by
+// the data in the record, like a codec failing, a Rating out of range or
+// an invalid Decimal, rather than by a programming error.
+func dataError(err error) bool {
+	var (
+		codecErr   ErrCodec
+		rangeErr   ErrRatingRange
+		decimalErr ErrInvalidDecimal
+	)
+	return errors.As(err, &codecErr) || errors.As(err, &rangeErr) || errors.As(err, &decimalErr)
+}
+
+// codecField is a field in a Fields struct that is handled by a codec.
+type codecField struct {
+	index int
+	name  string
+	codec Codec
+}
+
+// codecFields finds the fields of a Fields struct type that have a
+// registered codec, or a time layout (see layoutCodec). Only the direct
+// fields of the struct are considered; embedded structs are left to
+// encoding/json.
+func codecFields(typ reflect.Type) []codecField {
+	var fields []codecField
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" || f.Anonymous {
+			continue
+		}
+		name, ok := jsonFieldName(f)
+		if !ok {
+			continue
+		}
+		if codec, ok := lookupCodec(f.Type); ok {
+			fields = append(fields, codecField{index: i, name: name, codec: codec})
+		} else if codec, ok := layoutCodec(f); ok {
+			fields = append(fields, codecField{index: i, name: name, codec: codec})
+		}
+	}
+	return fields
+}
+
+// jsonFieldName returns the key encoding/json would use for f, or false
+// if the field is ignored by encoding/json.
+func jsonFieldName(f reflect.StructField) (string, bool) {
+	tag, ok := f.Tag.Lookup("json")
+	if !ok {
+		return f.Name, true
+	}
+	if tag == "-" {
+		return "", false
+	}
+	if i := strings.Index(tag, ","); i != -1 {
+		tag = tag[:i]
+	}
+	if tag == "" {
+		return f.Name, true
+	}
+	return tag, true
+}
+
+// encodeFields marshals the Fields struct, passing any fields with a
+// registered codec through that codec. A json.RawMessage is sent as it
+// is.
+func encodeFields(fields reflect.Value) ([]byte, error) {
+	if raw, ok := fields.Interface().(json.RawMessage); ok {
+		if len(raw) == 0 {
+			return []byte("{}"), nil
+		}
+		return raw, nil
+	}
+	if fields.Kind() != reflect.Struct {
+		return json.Marshal(fields.Interface())
+	}
+	cfs := codecFields(fields.Type())
+	renames := renamedFields(fields.Type())
+	if len(cfs) == 0 && len(renames) == 0 {
+		return json.Marshal(fields.Interface())
+	}
+
+	// the codec fields are left out of what encoding/json sees, since
+	// it may not be able to marshal their types.
+	skip := make(map[int]bool, len(cfs))
+	for _, cf := range cfs {
+		skip[cf.index] = true
+	}
+	cells, err := marshalCells(fields, skip)
+	if err != nil {
+		return nil, err
+	}
+	for _, cf := range cfs {
+		if cf.codec.Marshal == nil {
+			continue
+		}
+		f := fields.Field(cf.index)
+		v, err := cf.codec.Marshal(f.Interface())
+		if err != nil {
+			return nil, ErrCodec{Field: cf.name, Type: f.Type(), Err: err}
+		}
+		if v == nil {
+			continue
+		}
+		cell, err := json.Marshal(v)
+		if err != nil {
+			return nil, ErrCodec{Field: cf.name, Type: f.Type(), Err: err}
+		}
+		cells[cf.name] = cell
+	}
+	for key, name := range renames {
+		if cell, ok := cells[key]; ok {
+			delete(cells, key)
+			cells[name] = cell
+		}
+	}
+	return json.Marshal(cells)
+}
+
+// marshalCells marshals the fields of a Fields struct one at a time, the
+// way encoding/json would, except for those at the indexes in skip. The
+// cells of embedded structs are only kept where the struct's own fields
+// don't have the same name, as with encoding/json; embedded structs
+// that aren't exported are left out.
+func marshalCells(fields reflect.Value, skip map[int]bool) (map[string]json.RawMessage, error) {
+	cells := map[string]json.RawMessage{}
+	var embedded []reflect.Value
+	typ := fields.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		f, v := typ.Field(i), fields.Field(i)
+		if skip[i] {
+			continue
+		}
+		tag := strings.Split(f.Tag.Get("json"), ",")
+		if f.Anonymous && tag[0] == "" {
+			t := f.Type
+			if t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t.Kind() == reflect.Struct {
+				if f.PkgPath == "" && !(v.Kind() == reflect.Ptr && v.IsNil()) {
+					embedded = append(embedded, v)
+				}
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		name, ok := jsonFieldName(f)
+		if !ok || (containsString(tag[1:], "omitempty") && isEmptyValue(v)) {
+			continue
+		}
+		cell, err := json.Marshal(v.Interface())
+		if err != nil {
+			return nil, err
+		}
+		cells[name] = cell
+	}
+	for _, v := range embedded {
+		b, err := json.Marshal(v.Interface())
+		if err != nil {
+			return nil, err
+		}
+		inner := map[string]json.RawMessage{}
+		if err := json.Unmarshal(b, &inner); err != nil {
+			return nil, err
+		}
+		for name, cell := range inner {
+			if _, ok := cells[name]; !ok {
+				cells[name] = cell
+			}
+		}
+	}
+	return cells, nil
+}
+
+// isEmptyValue reports whether v is empty as omitempty means it.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// unmarshalRecord unmarshals a single record from the API into the
+// object pointed to by recordPtr, passing any fields with a registered
+// codec through that codec.
+func unmarshalRecord(data []byte, recordPtr interface{}) error {
+	fields := reflect.ValueOf(recordPtr).Elem().FieldByName("Fields")
+	if !fields.IsValid() || fields.Kind() != reflect.Struct {
+		return json.Unmarshal(data, recordPtr)
+	}
+	cfs := codecFields(fields.Type())
+	renames := renamedFields(fields.Type())
+	if len(cfs) == 0 && len(renames) == 0 {
+		return json.Unmarshal(data, recordPtr)
+	}
+
+	// move the cells of fields with an airtable name to the keys
+	// encoding/json expects, and pull the codec cells out of the record
+	// before handing it to encoding/json, which might not know what to
+	// do with them.
+	var (
+		record = map[string]json.RawMessage{}
+		cells  = map[string]json.RawMessage{}
+	)
+	if err := json.Unmarshal(data, &record); err != nil {
+		return err
+	}
+	if raw, ok := record["fields"]; ok {
+		if err := json.Unmarshal(raw, &cells); err != nil {
+			return err
+		}
+	}
+	for key := range renames {
+		delete(cells, key)
+	}
+	for key, name := range renames {
+		if cell, ok := cells[name]; ok {
+			delete(cells, name)
+			cells[key] = cell
+		}
+	}
+	pulled := make(map[string]json.RawMessage, len(cfs))
+	for _, cf := range cfs {
+		if cell, ok := cells[cf.name]; ok {
+			pulled[cf.name] = cell
+			delete(cells, cf.name)
+		}
+	}
+	raw, err := json.Marshal(cells)
+	if err != nil {
+		return err
+	}
+	record["fields"] = raw
+	if data, err = json.Marshal(record); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, recordPtr); err != nil {
+		return err
+	}
+
+	for _, cf := range cfs {
+		cell, ok := pulled[cf.name]
+		if !ok || cf.codec.Unmarshal == nil {
+			continue
+		}
+		f := fields.Field(cf.index)
+		if err := cf.codec.Unmarshal(cell, f.Addr().Interface()); err != nil {
+			return ErrCodec{Field: cf.name, Type: f.Type(), Err: err}
+		}
+	}
+	return nil
+}
+
+// hasCodecFields reports whether records of type typ need to go through
+// unmarshalRecord rather than being decoded directly by encoding/json.
+func hasCodecFields(typ reflect.Type) bool {
+	fields, ok := typ.FieldByName("Fields")
+	if !ok || fields.Type.Kind() != reflect.Struct {
+		return false
+	}
+	return len(codecFields(fields.Type)) > 0
+}
@@ -0,0 +1,233 @@
+package airtable_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brianloveswords/airtable"
+)
+
+// cents stores currency as an integer amount of cents but is sent to
+// Airtable as a dollar amount.
+type cents int64
+
+// computed is a read-only value: it's decoded but never sent.
+type computed string
+
+// lazy is a value encoding/json can't marshal on its own, so it's only
+// ever sent through its codec.
+type lazy struct {
+	get func() string
+	Get func() string
+}
+
+func init() {
+	airtable.RegisterCodec(cents(0), airtable.Codec{
+		Marshal: func(v interface{}) (interface{}, error) {
+			return float64(v.(cents)) / 100, nil
+		},
+		Unmarshal: func(data []byte, v interface{}) error {
+			var dollars float64
+			if err := json.Unmarshal(data, &dollars); err != nil {
+				return err
+			}
+			*v.(*cents) = cents(dollars*100 + 0.5)
+			return nil
+		},
+	})
+	airtable.RegisterCodec(computed(""), airtable.Codec{
+		Unmarshal: func(data []byte, v interface{}) error {
+			var s string
+			if err := json.Unmarshal(data, &s); err != nil {
+				return err
+			}
+			*v.(*computed) = computed("computed:" + s)
+			return nil
+		},
+	})
+	airtable.RegisterCodec(lazy{}, airtable.Codec{
+		Marshal: func(v interface{}) (interface{}, error) {
+			return v.(lazy).Get(), nil
+		},
+	})
+}
+
+type invoiceRecord struct {
+	airtable.Record
+	Fields struct {
+		Name    string
+		Total   cents    `json:"Total Due"`
+		Summary computed `json:"Summary,omitempty"`
+	}
+}
+
+func TestCodecDecode(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"records": [
+			{"id": "rec1", "fields": {"Name": "a", "Total Due": 12.34, "Summary": "x"}},
+			{"id": "rec2", "fields": {"Name": "b"}}
+		]}`)
+	})
+	defer done()
+	table := client.Table("Invoices")
+
+	invoices := []invoiceRecord{}
+	if err := table.List(&invoices, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(invoices) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(invoices))
+	}
+	if got := invoices[0].Fields.Total; got != 1234 {
+		t.Errorf("expected 1234 cents, got %d", got)
+	}
+	if got := invoices[0].Fields.Summary; got != "computed:x" {
+		t.Errorf("expected read-only codec to decode, got %q", got)
+	}
+	if got := invoices[1].Fields.Total; got != 0 {
+		t.Errorf("expected missing cell to stay zero, got %d", got)
+	}
+	if invoices[0].ID != "rec1" || invoices[0].Fields.Name != "a" {
+		t.Errorf("non-codec fields not decoded: %+v", invoices[0])
+	}
+}
+
+func TestCodecEncode(t *testing.T) {
+	var body struct{ Fields map[string]interface{} }
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(b, &body); err != nil {
+			t.Error(err)
+			return
+		}
+		fmt.Fprint(w, `{"id": "recNEW", "fields": {"Name": "a", "Total Due": 5}}`)
+	})
+	defer done()
+	table := client.Table("Invoices")
+
+	invoice := invoiceRecord{}
+	invoice.Fields.Name = "a"
+	invoice.Fields.Total = 500
+	invoice.Fields.Summary = "should not be sent"
+	if err := table.Create(&invoice); err != nil {
+		t.Fatal(err)
+	}
+
+	fields := body.Fields
+	if got := fields["Total Due"]; got != 5.0 {
+		t.Errorf("expected Total Due to be sent as 5, got %v", got)
+	}
+	if _, ok := fields["Summary"]; ok {
+		t.Errorf("expected read-only field to be omitted, got %v", fields)
+	}
+	if invoice.ID != "recNEW" || invoice.Fields.Total != 500 {
+		t.Errorf("expected record to be updated from response, got %+v", invoice)
+	}
+}
+
+func TestCodecEncodeUnmarshalable(t *testing.T) {
+	var body struct{ Fields map[string]interface{} }
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+			return
+		}
+		fmt.Fprint(w, `{"id": "recNEW", "fields": {"Name": "a", "Notes": "later"}}`)
+	})
+	defer done()
+	table := client.Table("Notes")
+
+	record := struct {
+		airtable.Record
+		Fields struct {
+			Name  string
+			Notes lazy
+			Skip  string `json:",omitempty"`
+		}
+	}{}
+	record.Fields.Name = "a"
+	record.Fields.Notes.Get = func() string { return "later" }
+	if err := table.Create(&record); err != nil {
+		t.Fatal(err)
+	}
+	if got := body.Fields["Notes"]; got != "later" {
+		t.Errorf("expected Notes to be sent through its codec, got %v", got)
+	}
+	if got := body.Fields["Name"]; got != "a" {
+		t.Errorf("expected Name to be sent, got %v", got)
+	}
+	if _, ok := body.Fields["Skip"]; ok {
+		t.Errorf("expected empty omitempty field to be left out, got %v", body.Fields)
+	}
+}
+
+func TestLayoutTag(t *testing.T) {
+	var sent map[string]interface{}
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		body := struct {
+			Fields map[string]interface{}
+		}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		sent = body.Fields
+		fmt.Fprint(w, `{"id": "rec1", "fields": {"Due": "2024-03-01", "Started At": "01/02/2024 09:30", "Logged": "2024-02-01T09:30:00.000Z"}}`)
+	})
+	defer done()
+
+	type event struct {
+		airtable.Record
+		Fields struct {
+			Due     time.Time  `layout:"2006-01-02"`
+			Started *time.Time `json:"Started At" layout:"02/01/2006 15:04"`
+			Logged  time.Time
+		}
+	}
+	record := event{}
+	record.Fields.Due = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	table := client.Table("Events")
+	if err := table.Create(&record); err != nil {
+		t.Fatal(err)
+	}
+	if sent["Due"] != "2024-03-01" {
+		t.Errorf("expected Due to be sent in its layout, got %v", sent["Due"])
+	}
+	if _, ok := sent["Started At"]; ok {
+		t.Errorf("expected the nil time to be left out, got %v", sent["Started At"])
+	}
+
+	started := time.Date(2024, 2, 1, 9, 30, 0, 0, time.UTC)
+	if record.Fields.Started == nil || !record.Fields.Started.Equal(started) {
+		t.Errorf("expected Started to be parsed with its layout, got %v", record.Fields.Started)
+	}
+	if !record.Fields.Logged.Equal(started) {
+		t.Errorf("expected Logged to be parsed as RFC 3339, got %v", record.Fields.Logged)
+	}
+}
+
+func TestLayoutTagClearable(t *testing.T) {
+	handler, bodies := recordBodies()
+	client, done := newTestClient(handler)
+	defer done()
+
+	type event struct {
+		airtable.Record
+		Fields struct {
+			Due     time.Time  `layout:"2006-01-02" airtable:",clearable"`
+			Started *time.Time `layout:"2006-01-02" airtable:",clearable"`
+			Ends    time.Time  `layout:"2006-01-02"`
+		}
+	}
+	record := event{}
+	record.ID = "rec1"
+	table := client.Table("Events")
+	if err := table.Update(&record); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"fields": {"Due":null,"Started":null}, "typecast": false}`
+	if (*bodies)[0] != want {
+		t.Errorf("unexpected body\n got: %s\nwant: %s", (*bodies)[0], want)
+	}
+}
@@ -0,0 +1,238 @@
+package airtable
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// CopyRecords copies the records in src matching options into dst, which
+// can be in a different base and use a different client, and returns
+// the number of records created. Records are read a page at a time and
+// created in batches of 10, so the whole table is never held in memory.
+//
+// Since there's no record struct, the names in options.Fields,
+// options.Sort and options.SortBy are Airtable field names. options may
+// be nil.
+//
+// fieldMapping renames fields from their name in src to their name in
+// dst; fields that aren't in the mapping keep their name, and fields
+// mapped to "" are dropped. The schema of dst is fetched from the
+// metadata API to drop fields that don't exist in dst or that are
+// computed (formulas, rollups, lookups, etc.) and can't be written.
+// Attachments are sent by URL so Airtable copies the files.
+//
+// If creating a batch fails, CopyRecords stops and returns the number of
+// records created before the failure along with the error. Use
+// CopyRecordsContext with a checkpoint to be able to resume from there.
+func CopyRecords(src, dst Table, options *Options, fieldMapping map[string]string) (int, error) {
+	return CopyRecordsContext(context.Background(), src, dst, options, fieldMapping, nil)
+}
+
+// CopyCheckpoint records how far a copy has got. It can be saved, as
+// JSON for example, and passed back in CopyOptions to resume a copy
+// that was interrupted.
+//
+// - Offset: offset of the page being copied; empty for the first page.
+//
+// - Done: how many records of that page have been copied.
+//
+// - Copied: how many records have been copied in all.
+//
+// - Complete: whether the copy got to the end. Resuming from a complete
+// checkpoint copies nothing.
+type CopyCheckpoint struct {
+	Offset   string `json:"offset"`
+	Done     int    `json:"done"`
+	Copied   int    `json:"copied"`
+	Complete bool   `json:"complete"`
+}
+
+// CopyOptions controls CopyRecordsContext.
+//
+// - Checkpoint: where to resume the copy from. It's updated after each
+// batch is created, so after a failure it holds the point to resume
+// from. Leave it zero to start from the beginning.
+//
+// - Progress: called with the checkpoint after each batch is created,
+// and once more when the copy is complete, e.g. to save the checkpoint
+// or to drive a progress bar.
+//
+// Resuming relies on the source pages being the same as before, so the
+// copy should be sorted or use a view, and the source shouldn't change
+// in between. Airtable offsets also expire after a while, so a copy
+// can't be resumed long after it was interrupted.
+type CopyOptions struct {
+	Checkpoint *CopyCheckpoint
+	Progress   func(CopyCheckpoint)
+}
+
+// CopyRecordsContext is like CopyRecords, but the requests are bound to
+// ctx and the copy can be checkpointed and resumed with copyOptions,
+// which may be nil. It returns the number of records created by this
+// call.
+func CopyRecordsContext(
+	ctx context.Context,
+	src, dst Table,
+	options *Options,
+	fieldMapping map[string]string,
+	copyOptions *CopyOptions,
+) (int, error) {
+	if copyOptions == nil {
+		copyOptions = &CopyOptions{}
+	}
+	checkpoint := copyOptions.Checkpoint
+	if checkpoint == nil {
+		checkpoint = &CopyCheckpoint{}
+	}
+	if checkpoint.Complete {
+		return 0, nil
+	}
+
+	schema, err := dst.client.Schema(ctx)
+	if err != nil {
+		return 0, err
+	}
+	table := schema.Table(dst.name)
+	if table == nil {
+		return 0, fmt.Errorf("airtable.CopyRecords: no table %s in destination schema", dst.name)
+	}
+
+	var query Options
+	if options != nil {
+		query = *options
+	}
+	query.typ = nil
+	query.Offset = checkpoint.Offset
+
+	copied := 0
+	for {
+		bytes, err := src.client.RequestContext(ctx, "GET", src.makePath(""), query)
+		if err != nil {
+			return copied, err
+		}
+		page := struct {
+			Records []struct {
+				Fields map[string]json.RawMessage
+			}
+			Offset string
+		}{}
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return copied, err
+		}
+
+		// skip the records of the page that were copied before the
+		// copy was interrupted.
+		var records []map[string]json.RawMessage
+		for i := checkpoint.Done; i < len(page.Records); i++ {
+			records = append(records, copyFields(page.Records[i].Fields, table, fieldMapping))
+		}
+		for len(records) > 0 {
+			n := maxBatchSize
+			if len(records) < n {
+				n = len(records)
+			}
+			created, err := dst.createRecords(ctx, records[:n])
+			copied += len(created)
+			checkpoint.Done += len(created)
+			checkpoint.Copied += len(created)
+			if err != nil {
+				return copied, err
+			}
+			if copyOptions.Progress != nil {
+				copyOptions.Progress(*checkpoint)
+			}
+			records = records[n:]
+		}
+
+		checkpoint.Offset = page.Offset
+		checkpoint.Done = 0
+		query.Offset = page.Offset
+		if query.Offset == "" {
+			break
+		}
+	}
+	checkpoint.Complete = true
+	if copyOptions.Progress != nil {
+		copyOptions.Progress(*checkpoint)
+	}
+	return copied, nil
+}
+
+// copyFields translates the fields of a record from the source table
+// into fields that can be written to the destination table.
+func copyFields(
+	fields map[string]json.RawMessage,
+	table *TableSchema,
+	fieldMapping map[string]string,
+) map[string]json.RawMessage {
+	copied := make(map[string]json.RawMessage, len(fields))
+	for name, value := range fields {
+		if mapped, ok := fieldMapping[name]; ok {
+			name = mapped
+		}
+		if name == "" {
+			continue
+		}
+		field := table.Field(name)
+		if field == nil || isComputedFieldType(field.Type) {
+			continue
+		}
+		if field.Type == "multipleAttachments" {
+			value = attachmentsByURL(value)
+		}
+		copied[name] = value
+	}
+	return copied
+}
+
+// attachmentsByURL strips attachments down to the URL and filename so
+// Airtable uploads a copy instead of referencing the original.
+func attachmentsByURL(value json.RawMessage) json.RawMessage {
+	var attachments []struct {
+		URL      string `json:"url"`
+		Filename string `json:"filename,omitempty"`
+	}
+	if err := json.Unmarshal(value, &attachments); err != nil {
+		return value
+	}
+	b, err := json.Marshal(attachments)
+	if err != nil {
+		return value
+	}
+	return b
+}
+
+// createRecords creates up to maxBatchSize records in a single request
+// and returns the created records.
+func (t *Table) createRecords(ctx context.Context, fields []map[string]json.RawMessage) ([]Record, error) {
+	type create struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	records := make([]create, len(fields))
+	for i, f := range fields {
+		if err := t.validateFieldSelects(ctx, f); err != nil {
+			return nil, err
+		}
+		records[i] = create{Fields: f}
+	}
+	b, err := json.Marshal(struct {
+		Records  []create `json:"records"`
+		Typecast bool     `json:"typecast,omitempty"`
+	}{records, t.typecast})
+	if err != nil {
+		return nil, err
+	}
+	res, err := t.client.RequestWithBodyContext(ctx, "POST", t.makePath(""), Options{}, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	created := struct {
+		Records []Record
+	}{}
+	if err := json.Unmarshal(res, &created); err != nil {
+		return nil, fmt.Errorf("airtable: could not unpack create response %s", err)
+	}
+	return created.Records, nil
+}
@@ -0,0 +1,149 @@
+package airtable_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestCopyRecords(t *testing.T) {
+	src, doneSrc := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("view"); got != "Old" {
+			t.Errorf("expected view to be passed along, got %q", got)
+		}
+		if r.URL.Query().Get("offset") == "" {
+			fmt.Fprint(w, `{"offset": "p2", "records": [
+				{"id": "rec1", "fields": {"Title": "a", "Author": "x", "Score": 1, "Secret": "s"}}
+			]}`)
+			return
+		}
+		fmt.Fprint(w, `{"records": [
+			{"id": "rec2", "fields": {"Title": "b", "Cover": [{"id": "att1", "url": "https://example.com/b.png", "filename": "b.png", "size": 10}]}}
+		]}`)
+	})
+	defer doneSrc()
+
+	var created []map[string]interface{}
+	dst, doneDst := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			fmt.Fprint(w, `{"tables": [{"id": "tblARCHIVE", "name": "Archive", "fields": [
+				{"id": "fld1", "name": "Title", "type": "singleLineText"},
+				{"id": "fld2", "name": "Writer", "type": "singleLineText"},
+				{"id": "fld3", "name": "Score", "type": "formula"},
+				{"id": "fld4", "name": "Cover", "type": "multipleAttachments"},
+				{"id": "fld5", "name": "Secret", "type": "singleLineText"}
+			]}]}`)
+			return
+		}
+		body := struct {
+			Records  []struct{ Fields map[string]interface{} }
+			Typecast bool
+		}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if !body.Typecast {
+			t.Error("expected typecast to be sent")
+		}
+		for _, record := range body.Records {
+			created = append(created, record.Fields)
+		}
+		fmt.Fprint(w, recordsResponse(len(created), len(body.Records), ""))
+	})
+	defer doneDst()
+
+	archive := dst.Table("Archive")
+	archive.SetTypecast(true)
+	n, err := airtable.CopyRecords(
+		src.Table("Books"),
+		archive,
+		&airtable.Options{View: "Old"},
+		map[string]string{"Author": "Writer", "Secret": ""},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 records copied, got %d", n)
+	}
+
+	b, _ := json.Marshal(created)
+	want := `[{"Title":"a","Writer":"x"},{"Cover":[{"filename":"b.png","url":"https://example.com/b.png"}],"Title":"b"}]`
+	if string(b) != want {
+		t.Errorf("unexpected records created\n got: %s\nwant: %s", b, want)
+	}
+}
+
+func TestCopyRecordsResume(t *testing.T) {
+	src, doneSrc := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "" {
+			fmt.Fprint(w, recordsResponse(0, 12, "p2"))
+			return
+		}
+		fmt.Fprint(w, recordsResponse(12, 3, ""))
+	})
+	defer doneSrc()
+
+	var (
+		posts   int
+		created int
+	)
+	dst, doneDst := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			fmt.Fprint(w, `{"tables": [{"id": "tblARCHIVE", "name": "Archive", "fields": []}]}`)
+			return
+		}
+		posts++
+		if posts == 2 {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"error": {"type": "INVALID_REQUEST"}}`)
+			return
+		}
+		body := struct {
+			Records []json.RawMessage
+		}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, recordsResponse(created, len(body.Records), ""))
+		created += len(body.Records)
+	})
+	defer doneDst()
+
+	checkpoint := &airtable.CopyCheckpoint{}
+	var progress []int
+	copyOptions := &airtable.CopyOptions{
+		Checkpoint: checkpoint,
+		Progress: func(c airtable.CopyCheckpoint) {
+			progress = append(progress, c.Copied)
+		},
+	}
+	n, err := airtable.CopyRecordsContext(context.Background(), src.Table("Books"), dst.Table("Archive"), nil, nil, copyOptions)
+	if err == nil {
+		t.Fatal("expected the second batch to fail")
+	}
+	if n != 10 || *checkpoint != (airtable.CopyCheckpoint{Done: 10, Copied: 10}) {
+		t.Errorf("unexpected checkpoint %+v after copying %d", checkpoint, n)
+	}
+
+	n, err = airtable.CopyRecordsContext(context.Background(), src.Table("Books"), dst.Table("Archive"), nil, nil, copyOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 5 || created != 15 || checkpoint.Copied != 15 {
+		t.Errorf("expected the remaining 5 records to be copied, got %d (%d in all)", n, created)
+	}
+	if fmt.Sprint(progress) != "[10 12 15 15]" || !checkpoint.Complete {
+		t.Errorf("unexpected progress %v", progress)
+	}
+
+	// resuming from a complete checkpoint copies nothing.
+	saved, _ := json.Marshal(checkpoint)
+	resumed := airtable.CopyCheckpoint{}
+	json.Unmarshal(saved, &resumed)
+	copyOptions.Checkpoint = &resumed
+	n, err = airtable.CopyRecordsContext(context.Background(), src.Table("Books"), dst.Table("Archive"), nil, nil, copyOptions)
+	if err != nil || n != 0 || created != 15 {
+		t.Errorf("expected nothing to be copied again, got %d (%d in all): %v", n, created, err)
+	}
+}
@@ -0,0 +1,334 @@
+// Package csvimport imports CSV files into Airtable tables.
+//
+// Columns are mapped to fields by a Config, which also says how to
+// convert each column's text to the field's type. Configs are usually
+// loaded from JSON:
+//
+//	{
+//		"typecast": true,
+//		"columns": [
+//			{"header": "Name", "field": "Title"},
+//			{"header": "Pages", "field": "Page Count", "type": "number"},
+//			{"header": "Published", "field": "Published", "type": "date", "layout": "02/01/2006"},
+//			{"header": "Tags", "field": "Tags", "type": "multipleSelects", "separator": ";"}
+//		]
+//	}
+//
+// Rows that can't be converted or created are written to a report
+// instead of stopping the import, and an import can be run with
+// ValidateOnly to check a file without creating anything.
+package csvimport
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brianloveswords/airtable"
+)
+
+// maxBatchSize is the most records Airtable accepts in one create.
+const maxBatchSize = 10
+
+// Type is how a column's text is converted for its field.
+type Type string
+
+// The supported column types. Text is the default.
+const (
+	Text        Type = "text"
+	Number      Type = "number"
+	Checkbox    Type = "checkbox"
+	Date        Type = "date"
+	Select      Type = "singleSelect"
+	MultiSelect Type = "multipleSelects"
+)
+
+func (t Type) valid() bool {
+	switch t {
+	case "", Text, Number, Checkbox, Date, Select, MultiSelect:
+		return true
+	}
+	return false
+}
+
+// Column maps a CSV column to a field.
+//
+// - Header: the column's header in the CSV file.
+//
+// - Field: name of the field the column goes in.
+//
+// - Type: how the text is converted. Defaults to Text.
+//
+// - Layout: for Date columns, the time.Parse layout of the dates.
+// Defaults to "2006-01-02".
+//
+// - Separator: for MultiSelect columns, what separates the options.
+// Defaults to ",".
+//
+// Empty cells are left out of the record.
+type Column struct {
+	Header    string `json:"header"`
+	Field     string `json:"field"`
+	Type      Type   `json:"type,omitempty"`
+	Layout    string `json:"layout,omitempty"`
+	Separator string `json:"separator,omitempty"`
+}
+
+// Config says how a CSV file maps to a table. Columns that aren't in
+// Columns are ignored. With Typecast, Airtable converts values that
+// don't match their field exactly, and creates select options that
+// don't exist yet instead of rejecting the row.
+type Config struct {
+	Columns  []Column `json:"columns"`
+	Typecast bool     `json:"typecast,omitempty"`
+}
+
+// LoadConfig reads a JSON config.
+func LoadConfig(r io.Reader) (*Config, error) {
+	config := &Config{}
+	if err := json.NewDecoder(r).Decode(config); err != nil {
+		return nil, fmt.Errorf("csvimport: reading config: %s", err)
+	}
+	return config, nil
+}
+
+// Importer imports CSV files into a table.
+//
+// - Client: client for the base. Required.
+//
+// - Table: name of the table to import into. Required.
+//
+// - Config: how the columns map to fields. Required.
+//
+// - ValidateOnly: convert every row and report the errors, but don't
+// create any records.
+//
+// - Report: where to write the rows that failed, as CSV with the row
+// number, the column (empty if the whole row failed), the value and
+// the error. Optional.
+type Importer struct {
+	Client       *airtable.Client
+	Table        string
+	Config       Config
+	ValidateOnly bool
+	Report       io.Writer
+}
+
+// Result summarizes an import. Rows are counted from 1, not including
+// the header.
+type Result struct {
+	Rows    int
+	Created int
+	Failed  int
+}
+
+// RowError is a row that couldn't be imported.
+type RowError struct {
+	Row    int
+	Column string
+	Value  string
+	Err    error
+}
+
+func (e RowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("row %d: %s", e.Row, e.Err)
+	}
+	return fmt.Sprintf("row %d, column %s (%q): %s", e.Row, e.Column, e.Value, e.Err)
+}
+
+// Import reads the CSV file from r and creates a record for each row.
+// Errors in individual rows are counted in the result and written to
+// the report; the returned error is for problems with the file as a
+// whole, the report, or the context.
+func (im *Importer) Import(ctx context.Context, r io.Reader) (Result, error) {
+	var result Result
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return result, fmt.Errorf("csvimport: reading header: %s", err)
+	}
+	columns, err := im.columnIndexes(header)
+	if err != nil {
+		return result, err
+	}
+
+	var report *csv.Writer
+	if im.Report != nil {
+		report = csv.NewWriter(im.Report)
+		defer report.Flush()
+	}
+	fail := func(e RowError) error {
+		result.Failed++
+		if report == nil {
+			return nil
+		}
+		return report.Write([]string{strconv.Itoa(e.Row), e.Column, e.Value, e.Err.Error()})
+	}
+
+	var (
+		batch []map[string]interface{}
+		rows  []int
+	)
+	flush := func() error {
+		if len(batch) == 0 || im.ValidateOnly {
+			batch, rows = nil, nil
+			return nil
+		}
+		if err := im.create(ctx, batch); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			for _, row := range rows {
+				if err := fail(RowError{Row: row, Err: err}); err != nil {
+					return err
+				}
+			}
+		} else {
+			result.Created += len(batch)
+		}
+		batch, rows = nil, nil
+		return nil
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		result.Rows++
+		if err != nil {
+			if err := fail(RowError{Row: result.Rows, Err: err}); err != nil {
+				return result, err
+			}
+			continue
+		}
+
+		fields := map[string]interface{}{}
+		var rowErr *RowError
+		for i, column := range im.Config.Columns {
+			text := record[columns[i]]
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			value, err := convert(column, text)
+			if err != nil {
+				rowErr = &RowError{Row: result.Rows, Column: column.Header, Value: text, Err: err}
+				break
+			}
+			fields[column.Field] = value
+		}
+		if rowErr != nil {
+			if err := fail(*rowErr); err != nil {
+				return result, err
+			}
+			continue
+		}
+
+		batch = append(batch, fields)
+		rows = append(rows, result.Rows)
+		if len(batch) == maxBatchSize {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return result, err
+	}
+	if report != nil {
+		report.Flush()
+		return result, report.Error()
+	}
+	return result, nil
+}
+
+// columnIndexes finds the index in the header of each configured
+// column.
+func (im *Importer) columnIndexes(header []string) ([]int, error) {
+	byHeader := make(map[string]int, len(header))
+	for i, h := range header {
+		byHeader[strings.TrimSpace(h)] = i
+	}
+	indexes := make([]int, len(im.Config.Columns))
+	for i, column := range im.Config.Columns {
+		if !column.Type.valid() {
+			return nil, fmt.Errorf("csvimport: column %q has unknown type %q", column.Header, column.Type)
+		}
+		index, ok := byHeader[column.Header]
+		if !ok {
+			return nil, fmt.Errorf("csvimport: no column %q in the file", column.Header)
+		}
+		indexes[i] = index
+	}
+	return indexes, nil
+}
+
+// convert turns a cell's text into the value for its field.
+func convert(column Column, text string) (interface{}, error) {
+	text = strings.TrimSpace(text)
+	switch column.Type {
+	case "", Text, Select:
+		return text, nil
+	case Number:
+		return strconv.ParseFloat(strings.Replace(text, ",", "", -1), 64)
+	case Checkbox:
+		switch strings.ToLower(text) {
+		case "true", "yes", "y", "1", "x", "checked":
+			return true, nil
+		case "false", "no", "n", "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("not a checkbox value")
+	case Date:
+		layout := column.Layout
+		if layout == "" {
+			layout = "2006-01-02"
+		}
+		t, err := time.Parse(layout, text)
+		if err != nil {
+			return nil, err
+		}
+		return t.Format("2006-01-02"), nil
+	case MultiSelect:
+		separator := column.Separator
+		if separator == "" {
+			separator = ","
+		}
+		var options []string
+		for _, option := range strings.Split(text, separator) {
+			if option = strings.TrimSpace(option); option != "" {
+				options = append(options, option)
+			}
+		}
+		return options, nil
+	}
+	panic("csvimport: unchecked column type " + string(column.Type))
+}
+
+// create creates a batch of records.
+func (im *Importer) create(ctx context.Context, batch []map[string]interface{}) error {
+	type record struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+	body := struct {
+		Records  []record `json:"records"`
+		Typecast bool     `json:"typecast,omitempty"`
+	}{Typecast: im.Config.Typecast}
+	for _, fields := range batch {
+		body.Records = append(body.Records, record{Fields: fields})
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	_, err = im.Client.RequestWithBodyContext(ctx, "POST", url.PathEscape(im.Table), nil, bytes.NewReader(b))
+	return err
+}
@@ -0,0 +1,104 @@
+package csvimport_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+	"github.com/brianloveswords/airtable/csvimport"
+)
+
+const config = `{
+	"typecast": true,
+	"columns": [
+		{"header": "Name", "field": "Title"},
+		{"header": "Pages", "field": "Page Count", "type": "number"},
+		{"header": "Published", "field": "Published", "type": "date", "layout": "02/01/2006"},
+		{"header": "Tags", "field": "Tags", "type": "multipleSelects", "separator": ";"}
+	]
+}`
+
+const books = `Name,Pages,Published,Tags,Ignored
+Dune,412,01/08/1965,sf; classic,x
+Hyperion,lots,26/05/1989,sf,x
+Solaris,,,,x
+`
+
+func TestImport(t *testing.T) {
+	var created []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := struct {
+			Records []struct {
+				Fields map[string]interface{}
+			}
+			Typecast bool
+		}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if !body.Typecast {
+			t.Error("expected typecast to be sent")
+		}
+		for _, r := range body.Records {
+			created = append(created, r.Fields)
+		}
+		fmt.Fprint(w, `{"records": []}`)
+	}))
+	defer server.Close()
+
+	cfg, err := csvimport.LoadConfig(strings.NewReader(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := &bytes.Buffer{}
+	importer := &csvimport.Importer{
+		Client: &airtable.Client{
+			APIKey:  "keyTEST",
+			BaseID:  "appTEST",
+			RootURL: server.URL,
+			Limiter: airtable.RateLimiter(0),
+		},
+		Table:  "Books",
+		Config: *cfg,
+		Report: report,
+	}
+
+	result, err := importer.Import(context.Background(), strings.NewReader(books))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rows != 3 || result.Created != 2 || result.Failed != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if len(created) != 2 {
+		t.Fatalf("expected 2 records to be created, got %v", created)
+	}
+	dune := created[0]
+	if dune["Title"] != "Dune" || dune["Page Count"] != 412.0 || dune["Published"] != "1965-08-01" {
+		t.Errorf("unexpected fields %v", dune)
+	}
+	if tags, _ := dune["Tags"].([]interface{}); len(tags) != 2 || tags[1] != "classic" {
+		t.Errorf("unexpected tags %v", dune["Tags"])
+	}
+	if _, ok := created[1]["Page Count"]; ok {
+		t.Errorf("expected empty cells to be left out, got %v", created[1])
+	}
+	if !strings.HasPrefix(report.String(), "2,Pages,lots,") {
+		t.Errorf("unexpected report %q", report.String())
+	}
+
+	// validating doesn't create anything.
+	created = nil
+	importer.ValidateOnly = true
+	result, err = importer.Import(context.Background(), strings.NewReader(books))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 0 || result.Failed != 1 || result.Created != 0 {
+		t.Errorf("expected a validation-only pass, got %+v and %v", result, created)
+	}
+}
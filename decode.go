@@ -0,0 +1,272 @@
+package airtable
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// decodeRecord unmarshals a single record from the API into the object
+// pointed to by recordPtr. Fields with a registered codec are passed
+// through the codec, and if the record embeds Record, the fields
+// present in the response and the raw JSON are recorded if the client
+// keeps them.
+func (c *Client) decodeRecord(data []byte, recordPtr interface{}) error {
+	if c.ZeroFill {
+		zeroFields(recordPtr)
+	}
+	if err := unmarshalRecord(data, recordPtr); err != nil {
+		return err
+	}
+	if !c.keepsMeta() {
+		return nil
+	}
+	record := embeddedRecord(recordPtr)
+	if record == nil {
+		return nil
+	}
+	record.meta = &recordMeta{}
+	if c.KeepFieldsPresent {
+		present := struct {
+			Fields map[string]json.RawMessage
+		}{}
+		if err := json.Unmarshal(data, &present); err != nil {
+			return err
+		}
+		record.meta.fields = make(map[string]bool, len(present.Fields))
+		for name := range present.Fields {
+			record.meta.fields[name] = true
+		}
+	}
+	if c.KeepRawJSON {
+		record.meta.raw = data
+	}
+	return nil
+}
+
+// keepsMeta reports whether decoded records get a recordMeta.
+func (c *Client) keepsMeta() bool {
+	return c.KeepFieldsPresent || c.KeepRawJSON
+}
+
+// embeddedRecord returns the Record embedded in the struct pointed to by
+// recordPtr, or nil if it doesn't embed one. If recordPtr points to a
+// Record, that's the one returned.
+func embeddedRecord(recordPtr interface{}) *Record {
+	if record, ok := recordPtr.(*Record); ok {
+		return record
+	}
+	v := reflect.ValueOf(recordPtr).Elem()
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	f, ok := v.Type().FieldByName("Record")
+	if !ok || !f.Anonymous || f.Type != recordType {
+		return nil
+	}
+	return v.FieldByIndex(f.Index).Addr().Interface().(*Record)
+}
+
+var recordType = reflect.TypeOf(Record{})
+
+// needsRecordDecode reports whether records of type typ have to be
+// decoded one at a time by decodeRecord rather than all at once by
+// encoding/json. Records that embed Record only do when the client
+// keeps what decodeRecord records about them.
+func (c *Client) needsRecordDecode(typ reflect.Type) bool {
+	if f, ok := typ.FieldByName("Record"); ok && f.Anonymous && f.Type == recordType && c.keepsMeta() {
+		return true
+	}
+	return hasCodecFields(typ) || hasRenamedFields(typ)
+}
+
+// decodeList decodes a page of a list response, appends the records to
+// the slice pointed to by listPtr and returns the offset for the next
+// page. Use a listDecoder when decoding many pages into the same list.
+func (c *Client) decodeList(bytes []byte, listPtr interface{}) (string, error) {
+	return c.newListDecoder(listPtr).decode(bytes)
+}
+
+// pageSize is the most records Airtable returns in a page.
+const pageSize = 100
+
+// listDecoder decodes the pages of a list response into a list,
+// reusing the response container from page to page to save
+// allocations.
+type listDecoder struct {
+	client    *Client
+	list      reflect.Value
+	typ       reflect.Type
+	perRecord bool
+	container reflect.Value
+}
+
+func (c *Client) newListDecoder(listPtr interface{}) *listDecoder {
+	typ := getRecordType(listPtr)
+	return &listDecoder{
+		client:    c,
+		list:      reflect.ValueOf(listPtr).Elem(),
+		typ:       typ,
+		perRecord: c.needsRecordDecode(typ),
+	}
+}
+
+// decode decodes a page, appends the records to the list and returns
+// the offset for the next page.
+func (d *listDecoder) decode(bytes []byte) (string, error) {
+	if !d.perRecord {
+		if !d.container.IsValid() {
+			d.container = reflect.New(responseContainerType(d.list.Type()))
+		} else {
+			resetContainer(d.container)
+		}
+		if err := json.Unmarshal(bytes, d.container.Interface()); err != nil {
+			return "", err
+		}
+		records := d.container.Elem().Field(0)
+		offset := d.container.Elem().Field(1).String()
+		growList(d.list, records.Len(), offset != "")
+		d.list.Set(reflect.AppendSlice(d.list, records))
+		return offset, nil
+	}
+
+	// otherwise the records have to be decoded one at a time.
+	page := struct {
+		Records []json.RawMessage
+		Offset  string
+	}{}
+	if err := json.Unmarshal(bytes, &page); err != nil {
+		return "", err
+	}
+	growList(d.list, len(page.Records), page.Offset != "")
+	for _, raw := range page.Records {
+		record := reflect.New(d.typ)
+		if err := d.client.decodeRecord(raw, record.Interface()); err != nil {
+			return "", err
+		}
+		d.list.Set(reflect.Append(d.list, record.Elem()))
+	}
+	return page.Offset, nil
+}
+
+// read decodes a page from a response body. A body that was spilled to
+// a temporary file or is being streamed from the connection is decoded
+// a record at a time, so the records are never all in memory as JSON.
+func (d *listDecoder) read(body *responseBody) (string, error) {
+	r := body.reader()
+	if r == nil {
+		return d.decode(body.data)
+	}
+
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return "", err
+	}
+	offset := ""
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		key, _ := token.(string)
+		switch {
+		case strings.EqualFold(key, "records"):
+			if err := d.readRecords(dec); err != nil {
+				return "", err
+			}
+		case strings.EqualFold(key, "offset"):
+			if err := dec.Decode(&offset); err != nil {
+				return "", err
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return "", err
+			}
+		}
+	}
+	return offset, expectDelim(dec, '}')
+}
+
+// offset returns the offset of the next page from an in-memory body
+// without decoding the records, or "" if there isn't one or the body
+// isn't in memory.
+func (b *responseBody) offset() string {
+	if b.reader() != nil {
+		return ""
+	}
+	page := struct{ Offset string }{}
+	json.Unmarshal(b.data, &page)
+	return page.Offset
+}
+
+// readRecords decodes the records array of a page one record at a time
+// and appends them to the list.
+func (d *listDecoder) readRecords(dec *json.Decoder) error {
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+	for dec.More() {
+		record := reflect.New(d.typ)
+		if d.perRecord {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return err
+			}
+			if err := d.client.decodeRecord(raw, record.Interface()); err != nil {
+				return err
+			}
+		} else if err := dec.Decode(record.Interface()); err != nil {
+			return err
+		}
+		d.list.Set(reflect.Append(d.list, record.Elem()))
+	}
+	return expectDelim(dec, ']')
+}
+
+// expectDelim reads the next token from dec and checks that it's delim.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("couldn't unmarshal response: expected %s, got %v", delim, token)
+	}
+	return nil
+}
+
+// resetContainer clears a response container for the next page. The
+// records left over from the last page are zeroed since encoding/json
+// may decode into them, which would leave stale values in fields that
+// are missing from the new page.
+func resetContainer(container reflect.Value) {
+	records := container.Elem().Field(0)
+	zero := reflect.Zero(records.Type().Elem())
+	for i := 0; i < records.Len(); i++ {
+		records.Index(i).Set(zero)
+	}
+	records.SetLen(0)
+	container.Elem().Field(1).SetString("")
+}
+
+// growList makes room in list for n more records, and if more pages are
+// coming, for another full page. The list at least doubles when it
+// grows, so appending page after page copies it a linear number of
+// times.
+func growList(list reflect.Value, n int, more bool) {
+	need := list.Len() + n
+	if list.Cap() >= need {
+		return
+	}
+	if more {
+		need += pageSize
+	}
+	if need < 2*list.Cap() {
+		need = 2 * list.Cap()
+	}
+	grown := reflect.MakeSlice(list.Type(), list.Len(), need)
+	reflect.Copy(grown, list)
+	list.Set(grown)
+}
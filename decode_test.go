@@ -0,0 +1,114 @@
+package airtable_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+type taskRecord struct {
+	airtable.Record
+	Fields struct {
+		Title string
+		Done  bool
+	}
+}
+
+func TestFieldsPresent(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"records": [
+			{"id": "rec1", "fields": {"Title": "a", "Done": false}},
+			{"id": "rec2", "fields": {"Title": "b"}}
+		]}`)
+	})
+	defer done()
+
+	table := client.Table("Tasks")
+	tasks := []taskRecord{}
+	if err := table.List(&tasks, nil); err != nil {
+		t.Fatal(err)
+	}
+	if present := tasks[0].FieldsPresent(); present != nil {
+		t.Errorf("expected no fields present by default, got %v", present)
+	}
+
+	client.KeepFieldsPresent = true
+	tasks = tasks[:0]
+	if err := table.List(&tasks, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !tasks[0].FieldsPresent()["Done"] {
+		t.Errorf("expected Done to be present in %v", tasks[0].FieldsPresent())
+	}
+	if present := tasks[1].FieldsPresent(); present["Done"] || !present["Title"] {
+		t.Errorf("expected only Title to be present, got %v", present)
+	}
+	if (taskRecord{}).FieldsPresent() != nil {
+		t.Error("expected records that weren't decoded to have no fields present")
+	}
+}
+
+func TestRawJSON(t *testing.T) {
+	const record = `{"id": "rec1", "fields": {"Title": "a", "Unmodeled": 1}}`
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, record)
+	})
+	defer done()
+
+	table := client.Table("Tasks")
+
+	task := taskRecord{}
+	if err := table.Get("rec1", &task); err != nil {
+		t.Fatal(err)
+	}
+	if task.RawJSON() != nil {
+		t.Errorf("expected no raw JSON by default, got %s", task.RawJSON())
+	}
+
+	client.KeepRawJSON = true
+	if err := table.Get("rec1", &task); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(task.RawJSON()); got != record {
+		t.Errorf("expected raw JSON %s, got %s", record, got)
+	}
+}
+
+func BenchmarkListPrefetch(b *testing.B) {
+	const pages = 500
+	var page bytes.Buffer
+	page.WriteString(`{"records": [`)
+	for i := 0; i < 100; i++ {
+		if i > 0 {
+			page.WriteString(",")
+		}
+		fmt.Fprintf(&page, `{"id": "rec%d", "fields": {"Title": "task %d", "Done": true}}`, i, i)
+	}
+	page.WriteString(`]`)
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		w.Write(page.Bytes())
+		if n+1 < pages {
+			fmt.Fprintf(w, `, "offset": "%d"`, n+1)
+		}
+		fmt.Fprint(w, `}`)
+	})
+	defer done()
+	table := client.Table("Tasks")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tasks := []taskRecord{}
+		if err := table.List(&tasks, &airtable.Options{Prefetch: true}); err != nil {
+			b.Fatal(err)
+		}
+		if len(tasks) != pages*100 {
+			b.Fatalf("expected %d tasks, got %d", pages*100, len(tasks))
+		}
+	}
+}
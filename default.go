@@ -0,0 +1,35 @@
+package airtable
+
+import "sync"
+
+var defaultClient struct {
+	sync.RWMutex
+	client *Client
+}
+
+// SetDefault sets the client used by the package-level helpers like
+// DefaultTable. It's meant for small scripts and tools; applications
+// should create and pass around their own Client.
+func SetDefault(c *Client) {
+	defaultClient.Lock()
+	defaultClient.client = c
+	defaultClient.Unlock()
+}
+
+// Default returns the client set by SetDefault, or nil if there isn't
+// one.
+func Default() *Client {
+	defaultClient.RLock()
+	defer defaultClient.RUnlock()
+	return defaultClient.client
+}
+
+// DefaultTable returns the table with the passed in name using the
+// client set by SetDefault. It panics if SetDefault hasn't been called.
+func DefaultTable(name string) Table {
+	c := Default()
+	if c == nil {
+		panic("airtable: no default Client, call SetDefault first")
+	}
+	return c.Table(name)
+}
@@ -0,0 +1,65 @@
+package airtable
+
+import "sync"
+
+// DefaultContentURL is the root URL of the content API, which attachment
+// uploads go to.
+var DefaultContentURL = "https://content.airtable.com"
+
+// Endpoint is the set of hosts a client talks to. Setting a client's
+// Endpoint configures all of them at once, for example to use a
+// data-residency region or to go through an API gateway:
+//
+//	client := airtable.Client{
+//		APIKey:   apiKey,
+//		BaseID:   baseID,
+//		Endpoint: airtable.MustEndpoint("gateway"),
+//	}
+//
+// - RootURL: root URL of the REST API.
+//
+// - ContentURL: root URL of the content API.
+type Endpoint struct {
+	RootURL    string
+	ContentURL string
+}
+
+// PublicEndpoint is Airtable's public API, which clients use unless
+// they're configured otherwise.
+var PublicEndpoint = Endpoint{
+	RootURL:    DefaultRootURL,
+	ContentURL: DefaultContentURL,
+}
+
+var endpointRegistry = struct {
+	sync.RWMutex
+	endpoints map[string]Endpoint
+}{endpoints: map[string]Endpoint{"public": PublicEndpoint}}
+
+// RegisterEndpoint registers a named endpoint preset, replacing any
+// preset previously registered with that name, so the endpoint can be
+// picked by name, for example from a configuration file. The preset
+// "public" is registered as PublicEndpoint.
+func RegisterEndpoint(name string, endpoint Endpoint) {
+	endpointRegistry.Lock()
+	endpointRegistry.endpoints[name] = endpoint
+	endpointRegistry.Unlock()
+}
+
+// LookupEndpoint returns the endpoint preset registered with name.
+func LookupEndpoint(name string) (Endpoint, bool) {
+	endpointRegistry.RLock()
+	endpoint, ok := endpointRegistry.endpoints[name]
+	endpointRegistry.RUnlock()
+	return endpoint, ok
+}
+
+// MustEndpoint is like LookupEndpoint but panics if there's no preset
+// registered with name.
+func MustEndpoint(name string) Endpoint {
+	endpoint, ok := LookupEndpoint(name)
+	if !ok {
+		panic("airtable: no endpoint registered as " + name)
+	}
+	return endpoint
+}
@@ -0,0 +1,43 @@
+package airtable_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestEndpoint(t *testing.T) {
+	requests := 0
+	backend, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"id": "rec1", "fields": {}}`)
+	})
+	defer done()
+
+	airtable.RegisterEndpoint("test", airtable.Endpoint{
+		RootURL:    backend.RootURL,
+		ContentURL: "https://content.example.com",
+	})
+	client := &airtable.Client{
+		APIKey:   "keyTEST",
+		BaseID:   "appTEST",
+		Limiter:  airtable.RateLimiter(0),
+		Endpoint: airtable.MustEndpoint("test"),
+	}
+	table := client.Table("Tasks")
+	if err := table.Get("rec1", &taskRecord{}); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("expected the request to go to the endpoint's RootURL")
+	}
+	if client.ContentURL != "https://content.example.com" {
+		t.Errorf("expected ContentURL from the endpoint, got %s", client.ContentURL)
+	}
+
+	if _, ok := airtable.LookupEndpoint("public"); !ok {
+		t.Error("expected the public endpoint to be registered")
+	}
+}
@@ -0,0 +1,26 @@
+package airtable_test
+
+import (
+	"fmt"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func ExampleDefaultTable() {
+	// Set the default client once, e.g. at the top of main.
+	airtable.SetDefault(&airtable.Client{
+		APIKey: "keyXXXXXXXXXXXXXX",
+		BaseID: "appwNa5g4gHCVZQPm",
+	})
+
+	// Then use it anywhere without passing the client around.
+	books := airtable.DefaultTable("Public Domain Books")
+
+	bestBooks := []PublicDomainBookRecord{}
+	books.List(&bestBooks, &airtable.Options{
+		Filter: `{Rating} >= 4`,
+	})
+	for _, book := range bestBooks {
+		fmt.Println(book.String())
+	}
+}
@@ -0,0 +1,34 @@
+package airtable_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestExists(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v0/appTEST/Tasks/rec1":
+			fmt.Fprint(w, `{"id": "rec1", "fields": {}}`)
+		case "/v0/appTEST/Tasks/recGONE":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": {"type": "MODEL_ID_NOT_FOUND"}}`)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error": {"type": "INVALID_PERMISSIONS"}}`)
+		}
+	})
+	defer done()
+
+	table := client.Table("Tasks")
+	if ok, err := table.Exists("rec1"); !ok || err != nil {
+		t.Errorf("expected rec1 to exist, got %t, %v", ok, err)
+	}
+	if ok, err := table.Exists("recGONE"); ok || err != nil {
+		t.Errorf("expected recGONE not to exist, got %t, %v", ok, err)
+	}
+	if _, err := table.Exists("recSECRET"); err == nil {
+		t.Error("expected other errors to be returned")
+	}
+}
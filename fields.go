@@ -1,8 +1,17 @@
 package airtable
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"fmt"
+	"math"
+	"math/big"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Attachment type. When creating a new attachment, only URL and
@@ -13,10 +22,16 @@ type Attachment []struct {
 	Filename   string `json:"filename"`
 	Size       float64
 	Type       string
-	Thumbnails struct {
-		Small AttachmentThumbnail
-		Large AttachmentThumbnail
-	}
+	Thumbnails AttachmentThumbnails
+}
+
+// AttachmentThumbnails are the thumbnails Airtable makes of images and
+// documents. Full is the largest Airtable renders, up to the size of the
+// original. Attachments Airtable can't make thumbnails of have none.
+type AttachmentThumbnails struct {
+	Small AttachmentThumbnail
+	Large AttachmentThumbnail
+	Full  AttachmentThumbnail
 }
 
 // AttachmentThumbnail holds the details of an individual thumbnail
@@ -26,62 +41,629 @@ type AttachmentThumbnail struct {
 	Height float64
 }
 
-// TODO: make MultiSelect more useful. It's a natural fit for a Set
-// type, but we don't have sets out of the box and it currently seems
-// frivolous to pull in an external dependency or even make a naive set
-// type just for this.
-
-// MultiSelect type. Alias for string slice.
+// MultiSelect is the value of a multiple select field. It's a set of
+// choices that keeps them in the order they were added; duplicates are
+// left out when it's sent, since Airtable rejects them.
 type MultiSelect []string
 
-// TODO: make RecordLink more useful. For example, if we know what table
-// the record links are supposed to come from, we could automatically
-// hydrate those links instead of returning strings. We could also
-// automatically create new records when necessary if the linked record
-// object is novel in a Create operation.
+// Has reports whether choice is selected.
+func (m MultiSelect) Has(choice string) bool {
+	for _, c := range m {
+		if c == choice {
+			return true
+		}
+	}
+	return false
+}
+
+// Add selects the choices that aren't selected yet.
+func (m *MultiSelect) Add(choices ...string) {
+	for _, choice := range choices {
+		if !m.Has(choice) {
+			*m = append(*m, choice)
+		}
+	}
+}
+
+// Remove unselects choices.
+func (m *MultiSelect) Remove(choices ...string) {
+	kept := (*m)[:0]
+	for _, c := range *m {
+		if !MultiSelect(choices).Has(c) {
+			kept = append(kept, c)
+		}
+	}
+	*m = kept
+}
+
+// Union returns the choices selected in m or other, those of m first.
+func (m MultiSelect) Union(other MultiSelect) MultiSelect {
+	union := MultiSelect{}
+	union.Add(m...)
+	union.Add(other...)
+	return union
+}
+
+// MarshalJSON sends the choices in order without duplicates.
+func (m MultiSelect) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal([]string(MultiSelect(nil).Union(m)))
+}
 
-// RecordLink type. Alias for string slice.
+// RecordLink type. Alias for string slice. When the table has a
+// LinkBinding for the field (see Table.BindLink), it may also contain
+// natural keys which are resolved to record IDs on Create and Update.
+// Use Hydrate to fetch the linked records.
 type RecordLink []string
 
-// FormulaResult can be a string, number or error.
+// Hydrate fetches the linked records from table, the table the field
+// links to, and appends them to the slice pointed to by listPtr, in the
+// order of the links, as GetMany does: in batches, using the client's
+// RecordCache, and skipping links to records that no longer exist and
+// natural keys that haven't been resolved.
+//
+// listPtr has the same requirements as in List.
+func (l RecordLink) Hydrate(table Table, listPtr interface{}) error {
+	return l.HydrateContext(context.Background(), table, listPtr)
+}
+
+// HydrateContext is like Hydrate but the requests are bound to ctx.
+func (l RecordLink) HydrateContext(ctx context.Context, table Table, listPtr interface{}) error {
+	ids := make([]string, 0, len(l))
+	for _, id := range l {
+		if isRecordID(id) {
+			ids = append(ids, id)
+		}
+	}
+	return table.GetManyContext(ctx, ids, listPtr)
+}
+
+// Collaborator is the value of a collaborator field. Responses have all
+// of ID, Email and Name. To set a collaborator, only ID or Email is
+// needed; ID is sent if it's set, or else Email. The zero Collaborator
+// is sent as null, which clears the cell.
+type Collaborator struct {
+	ID    string `json:"id,omitempty"`
+	Email string `json:"email,omitempty"`
+	Name  string `json:"name,omitempty"`
+}
+
+// MarshalJSON sends the collaborator by ID or by email.
+func (c Collaborator) MarshalJSON() ([]byte, error) {
+	switch {
+	case c.ID != "":
+		return json.Marshal(map[string]string{"id": c.ID})
+	case c.Email != "":
+		return json.Marshal(map[string]string{"email": c.Email})
+	}
+	return []byte("null"), nil
+}
+
+// ActorKind is the kind of actor that made or changed a record.
+type ActorKind string
+
+// ActorUser is a collaborator, ActorAutomation an automation and
+// ActorForm a form submission.
+const (
+	ActorUser       ActorKind = "user"
+	ActorAutomation ActorKind = "automation"
+	ActorForm       ActorKind = "form"
+)
+
+// Actor is who made or last changed a record, as reported by created by
+// and last modified by fields. Kind is taken from the type Airtable
+// gives the actor, and is ActorUser when it doesn't give one.
+type Actor struct {
+	ID    string
+	Email string
+	Name  string
+	Kind  ActorKind
+}
+
+// UnmarshalJSON decodes the collaborator object Airtable sends for the
+// actor.
+func (a *Actor) UnmarshalJSON(b []byte) error {
+	var v struct {
+		ID    string `json:"id"`
+		Email string `json:"email"`
+		Name  string `json:"name"`
+		Type  string `json:"type"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*a = Actor{ID: v.ID, Email: v.Email, Name: v.Name, Kind: ActorKind(v.Type)}
+	if a.Kind == "" && a.ID != "" {
+		a.Kind = ActorUser
+	}
+	return nil
+}
+
+// CreatedBy is the value of a created by field. It's computed, so it's
+// never sent on writes.
+type CreatedBy struct {
+	Actor
+}
+
+// LastModifiedBy is the value of a last modified by field. It's
+// computed, so it's never sent on writes.
+type LastModifiedBy struct {
+	Actor
+}
+
+func (CreatedBy) readonlyField()      {}
+func (LastModifiedBy) readonlyField() {}
+
+// LastModifiedTime is the value of a last modified time field: when any
+// of the record's fields, or only the fields the field watches (see
+// FieldOptions.ReferencedFieldIDs), last changed. It's zero if they
+// haven't changed since the record was made. It's computed, so it's
+// never sent on writes.
+type LastModifiedTime struct {
+	time.Time
+}
+
+func (LastModifiedTime) readonlyField() {}
+
+// AutoNumber is the value of an autonumber field. It's computed, so
+// it's never sent on writes.
+type AutoNumber int
+
+func (AutoNumber) readonlyField() {}
+
+// SyncSource is the value of the source field of a table synced from
+// other bases or services with Airtable Sync: the source the record is
+// synced from. Airtable sends it as the source's name or as an object
+// with its ID, name and color; either way is decoded. It's computed, so
+// it's never sent on writes.
+type SyncSource struct {
+	ID    string
+	Name  string
+	Color string
+}
+
+func (SyncSource) readonlyField() {}
+
+// UnmarshalJSON decodes the source from its name or from an object.
+func (s *SyncSource) UnmarshalJSON(b []byte) error {
+	var name *string
+	if err := json.Unmarshal(b, &name); err == nil {
+		*s = SyncSource{}
+		if name != nil {
+			s.Name = *name
+		}
+		return nil
+	}
+	var v struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("airtable: could not parse sync source %s", b)
+	}
+	*s = SyncSource{ID: v.ID, Name: v.Name, Color: v.Color}
+	return nil
+}
+
+// PhoneNumber is the value of a phone number field. Surrounding space
+// is trimmed when it's decoded. See Client.ValidateFormats for checking
+// it before it's sent.
+type PhoneNumber string
+
+// Email is the value of an email field. Surrounding space is trimmed and
+// the domain lowercased when it's decoded. See Client.ValidateFormats
+// for checking it before it's sent.
+type Email string
+
+// URL is the value of a URL field. Surrounding space is trimmed when
+// it's decoded. See Client.ValidateFormats for checking it before it's
+// sent.
+type URL string
+
+var phoneNumberPattern = regexp.MustCompile(`^\+?[0-9][0-9 ().-]*[0-9](\s*(x|ext\.?)\s*[0-9]+)?$`)
+
+// Validate checks that p looks like a phone number: digits, optionally
+// starting with + and separated by spaces, dots, dashes or parentheses,
+// with an optional extension. An empty PhoneNumber is valid.
+func (p PhoneNumber) Validate() error {
+	if p == "" {
+		return nil
+	}
+	digits := 0
+	for _, r := range p {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	if !phoneNumberPattern.MatchString(string(p)) || digits < 7 {
+		return fmt.Errorf("%q is not a phone number", string(p))
+	}
+	return nil
+}
+
+// Validate checks that e is a bare email address, without a display
+// name. An empty Email is valid.
+func (e Email) Validate() error {
+	if e == "" {
+		return nil
+	}
+	addr, err := mail.ParseAddress(string(e))
+	if err != nil || addr.Address != string(e) {
+		return fmt.Errorf("%q is not an email address", string(e))
+	}
+	return nil
+}
+
+// Validate checks that u is an absolute URL. An empty URL is valid.
+func (u URL) Validate() error {
+	if u == "" {
+		return nil
+	}
+	parsed, err := url.Parse(string(u))
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return fmt.Errorf("%q is not an absolute URL", string(u))
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes the phone number, trimmed.
+func (p *PhoneNumber) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalTrimmed(b)
+	*p = PhoneNumber(s)
+	return err
+}
+
+// UnmarshalJSON decodes the email address, trimmed and with its domain
+// lowercased.
+func (e *Email) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalTrimmed(b)
+	if i := strings.LastIndex(s, "@"); i != -1 {
+		s = s[:i] + strings.ToLower(s[i:])
+	}
+	*e = Email(s)
+	return err
+}
+
+// UnmarshalJSON decodes the URL, trimmed.
+func (u *URL) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalTrimmed(b)
+	*u = URL(s)
+	return err
+}
+
+// unmarshalTrimmed decodes the string b, or null, without surrounding
+// space.
+func unmarshalTrimmed(b []byte) (string, error) {
+	var s *string
+	if err := json.Unmarshal(b, &s); err != nil || s == nil {
+		return "", err
+	}
+	return strings.TrimSpace(*s), nil
+}
+
+// Decimal is the value of a currency, percent or number field, kept as
+// the decimal text Airtable sends so no precision is lost to float64,
+// and sent back exactly as it is. The zero Decimal is an empty cell and
+// is sent as null.
+type Decimal string
+
+// decimalPattern matches JSON numbers.
+var decimalPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// ErrInvalidDecimal is the error for a Decimal that isn't a number.
+type ErrInvalidDecimal struct {
+	Value string
+}
+
+func (e ErrInvalidDecimal) Error() string {
+	return fmt.Sprintf("airtable: invalid decimal %q", e.Value)
+}
+
+// ParseDecimal parses s, a decimal number like "1234.56" or "-1e-3". It
+// returns an ErrInvalidDecimal if s isn't one.
+func ParseDecimal(s string) (Decimal, error) {
+	if !decimalPattern.MatchString(s) {
+		return "", ErrInvalidDecimal{Value: s}
+	}
+	return Decimal(s), nil
+}
+
+// UnmarshalJSON keeps the number as it's written in b.
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*d = ""
+		return nil
+	}
+	v, err := ParseDecimal(string(b))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// MarshalJSON writes the number as it is, or null if d is empty. Create
+// and Update return an ErrInvalidDecimal if it isn't a number.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if d == "" {
+		return []byte("null"), nil
+	}
+	if _, err := ParseDecimal(string(d)); err != nil {
+		return nil, err
+	}
+	return []byte(d), nil
+}
+
+// Rat returns the exact value of d, or false if d is empty or invalid.
+func (d Decimal) Rat() (*big.Rat, bool) {
+	if _, err := ParseDecimal(string(d)); err != nil {
+		return nil, false
+	}
+	return new(big.Rat).SetString(string(d))
+}
+
+// Float64 returns d as the nearest float64, or 0 if d is empty.
+func (d Decimal) Float64() (float64, error) {
+	if d == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(string(d), 64)
+}
+
+// Rating is the value of a rating field, from 1 to the field's max,
+// which is at most 10. The zero Rating is an empty cell and is sent as
+// null. Ratings outside of 0 to 10 can't be sent; Create and Update
+// return an ErrRatingRange for them.
+type Rating int
+
+// MaxRating is the highest max a rating field can have.
+const MaxRating = 10
+
+// ErrRatingRange is the error for a Rating outside of 0 to Max.
+type ErrRatingRange struct {
+	Value int
+	Max   int
+}
+
+func (e ErrRatingRange) Error() string {
+	return fmt.Sprintf("airtable: rating %d out of range 0 to %d", e.Value, e.Max)
+}
+
+// Check returns an ErrRatingRange if r is outside of 0 to max, the max
+// of the field it's for.
+func (r Rating) Check(max int) error {
+	if r < 0 || int(r) > max {
+		return ErrRatingRange{Value: int(r), Max: max}
+	}
+	return nil
+}
+
+// MarshalJSON sends the rating, or null if it's 0.
+func (r Rating) MarshalJSON() ([]byte, error) {
+	if err := r.Check(MaxRating); err != nil {
+		return nil, err
+	}
+	if r == 0 {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Itoa(int(r))), nil
+}
+
+// FormulaResult can be a string, number, array or error. Numbers that
+// aren't finite, which Airtable sends as {"specialValue": "NaN"} and so
+// on, are decoded to NaN or an infinity; see IsNaN and IsInf. Raw holds
+// the JSON the result was decoded from. It's computed, so it's never
+// sent on writes.
 type FormulaResult struct {
 	Number *float64
 	String *string
+	Array  []interface{}
 	Error  *string
+	Raw    json.RawMessage
 }
 
-// UnmarshalJSON tries to figure out if this is an error, a string or
-// a number.
+func (FormulaResult) readonlyField() {}
+
+// UnmarshalJSON tries to figure out if this is an error, a string, a
+// number or an array. Values that are none of those are kept in Raw
+// and reported with an error.
 func (f *FormulaResult) UnmarshalJSON(b []byte) error {
-	i := new(interface{})
-	if err := json.Unmarshal(b, &i); err != nil {
+	*f = FormulaResult{Raw: append(json.RawMessage(nil), b...)}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
 		return err
 	}
-	switch v := (*i).(type) {
+	switch v := v.(type) {
+	case nil:
 	case string:
 		f.String = &v
 	case float64:
 		f.Number = &v
+	case []interface{}:
+		f.Array = v
 	case map[string]interface{}:
+		if special, ok := v["specialValue"].(string); ok {
+			n, ok := specialValues[special]
+			if !ok {
+				return fmt.Errorf("airtable: unknown formula special value %q", special)
+			}
+			f.Number = &n
+			return nil
+		}
 		err, ok := v["error"].(string)
 		if !ok {
-			panic("parse error")
+			return fmt.Errorf("airtable: could not parse formula result %s", b)
 		}
 		f.Error = &err
 	default:
-		log.Fatal("couldn't parse Formula type as number, string or error")
+		return fmt.Errorf("airtable: could not parse formula result %s as a number, string, array or error", b)
 	}
 	return nil
 }
 
+// RollupResult is the value of a rollup field: a number, a string, an
+// array, like the result of ARRAYUNIQUE, or an error. Numbers that
+// aren't finite are decoded as in FormulaResult. It's computed, so it's
+// never sent on writes.
+type RollupResult struct {
+	Number *float64
+	String *string
+	Array  []interface{}
+	Error  *string
+}
+
+func (RollupResult) readonlyField() {}
+
+// UnmarshalJSON figures out which kind of result b is.
+func (r *RollupResult) UnmarshalJSON(b []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*r = RollupResult{}
+	switch v := v.(type) {
+	case nil:
+	case string:
+		r.String = &v
+	case float64:
+		r.Number = &v
+	case []interface{}:
+		r.Array = v
+	case map[string]interface{}:
+		if special, ok := v["specialValue"].(string); ok {
+			n, ok := specialValues[special]
+			if !ok {
+				return fmt.Errorf("airtable: unknown rollup special value %q", special)
+			}
+			r.Number = &n
+			return nil
+		}
+		err, ok := v["error"].(string)
+		if !ok {
+			return fmt.Errorf("airtable: could not parse rollup result %s", b)
+		}
+		r.Error = &err
+	default:
+		return fmt.Errorf("airtable: could not parse rollup result %s", b)
+	}
+	return nil
+}
+
+// Value returns the number, string or array the rollup resulted in, or
+// false if it's an error or empty.
+func (r *RollupResult) Value() (v interface{}, ok bool) {
+	switch {
+	case r.Number != nil:
+		return *r.Number, true
+	case r.String != nil:
+		return *r.String, true
+	case r.Array != nil:
+		return r.Array, true
+	}
+	return nil, false
+}
+
+// Lookup is the value of a lookup field: the values of a field of the
+// linked records, which can be numbers, strings or objects, like
+// attachments or collaborators. It's computed, so it's never sent on
+// writes.
+type Lookup []json.RawMessage
+
+func (Lookup) readonlyField() {}
+
+// Raw returns the values as JSON.
+func (l Lookup) Raw() []json.RawMessage {
+	return []json.RawMessage(l)
+}
+
+// Strings returns the values that are strings, in order.
+func (l Lookup) Strings() []string {
+	var values []string
+	for _, raw := range l {
+		var s *string
+		if err := json.Unmarshal(raw, &s); err == nil && s != nil {
+			values = append(values, *s)
+		}
+	}
+	return values
+}
+
+// Floats returns the values that are numbers, in order.
+func (l Lookup) Floats() []float64 {
+	var values []float64
+	for _, raw := range l {
+		var f *float64
+		if err := json.Unmarshal(raw, &f); err == nil && f != nil {
+			values = append(values, *f)
+		}
+	}
+	return values
+}
+
+// Decode decodes the values into the slice pointed to by v, for values
+// that are objects, e.g. a *[]Collaborator.
+func (l Lookup) Decode(v interface{}) error {
+	b, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// specialValues are the numbers Airtable represents with a
+// "specialValue" object since JSON can't represent them.
+var specialValues = map[string]float64{
+	"NaN":       math.NaN(),
+	"Infinity":  math.Inf(1),
+	"-Infinity": math.Inf(-1),
+}
+
+// IsNaN reports whether the result is the number NaN, such as the
+// result of dividing zero by zero.
+func (f *FormulaResult) IsNaN() bool {
+	return f.Number != nil && math.IsNaN(*f.Number)
+}
+
+// IsInf reports whether the result is a positive or negative infinity,
+// such as the result of dividing by zero.
+func (f *FormulaResult) IsInf() bool {
+	return f.Number != nil && math.IsInf(*f.Number, 0)
+}
+
 // Value returns the underlying value if the formula results is a
-// string or a number, otherwise return nil pointer and false
+// string, a number or an array, otherwise return nil and false.
 func (f *FormulaResult) Value() (v interface{}, ok bool) {
-	if f.Error != nil {
-		return nil, false
-	}
-	if f.String != nil {
+	switch {
+	case f.String != nil:
 		return *f.String, true
+	case f.Number != nil:
+		return *f.Number, true
+	case f.Array != nil:
+		return f.Array, true
+	}
+	return nil, false
+}
+
+// AsString returns the result if it's a string.
+func (f *FormulaResult) AsString() (string, bool) {
+	if f.String == nil {
+		return "", false
+	}
+	return *f.String, true
+}
+
+// AsFloat returns the result if it's a number.
+func (f *FormulaResult) AsFloat() (float64, bool) {
+	if f.Number == nil {
+		return 0, false
 	}
 	return *f.Number, true
 }
+
+// AsArray returns the result if it's an array.
+func (f *FormulaResult) AsArray() ([]interface{}, bool) {
+	return f.Array, f.Array != nil
+}
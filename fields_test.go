@@ -0,0 +1,452 @@
+package airtable_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestFormulaResultSpecialValues(t *testing.T) {
+	var cells struct {
+		NaN, Inf, NegInf, Number airtable.FormulaResult
+	}
+	err := json.Unmarshal([]byte(`{
+		"NaN": {"specialValue": "NaN"},
+		"Inf": {"specialValue": "Infinity"},
+		"NegInf": {"specialValue": "-Infinity"},
+		"Number": 42
+	}`), &cells)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cells.NaN.IsNaN() || cells.NaN.IsInf() {
+		t.Error("expected NaN")
+	}
+	if !cells.Inf.IsInf() || *cells.Inf.Number < 0 {
+		t.Error("expected positive infinity")
+	}
+	if !cells.NegInf.IsInf() || *cells.NegInf.Number > 0 {
+		t.Error("expected negative infinity")
+	}
+	if cells.Number.IsNaN() || cells.Number.IsInf() {
+		t.Error("expected a finite number")
+	}
+
+	var unknown airtable.FormulaResult
+	if err := json.Unmarshal([]byte(`{"specialValue": "Huge"}`), &unknown); err == nil {
+		t.Error("expected an error for an unknown special value")
+	}
+}
+
+func TestCollaborator(t *testing.T) {
+	var cells struct {
+		Owner    airtable.Collaborator
+		Watchers []airtable.Collaborator
+	}
+	err := json.Unmarshal([]byte(`{
+		"Owner": {"id": "usrA", "email": "a@example.com", "name": "A"},
+		"Watchers": [{"id": "usrB", "email": "b@example.com", "name": "B"}]
+	}`), &cells)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cells.Owner.ID != "usrA" || cells.Owner.Email != "a@example.com" || cells.Owner.Name != "A" {
+		t.Errorf("unexpected owner %+v", cells.Owner)
+	}
+
+	cells.Watchers = append(cells.Watchers, airtable.Collaborator{Email: "c@example.com"})
+	b, err := json.Marshal(cells)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"Owner":{"id":"usrA"},"Watchers":[{"id":"usrB"},{"email":"c@example.com"}]}`
+	if string(b) != want {
+		t.Errorf("unexpected JSON\n got: %s\nwant: %s", b, want)
+	}
+	if b, _ := json.Marshal(airtable.Collaborator{}); string(b) != "null" {
+		t.Errorf("expected the zero collaborator to be null, got %s", b)
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	var cells struct {
+		Price, Discount, Empty airtable.Decimal
+	}
+	err := json.Unmarshal([]byte(`{"Price": 1234567890.123456789, "Discount": 0.1, "Empty": null}`), &cells)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cells.Price != "1234567890.123456789" || cells.Discount != "0.1" || cells.Empty != "" {
+		t.Errorf("unexpected cells %+v", cells)
+	}
+	if r, ok := cells.Discount.Rat(); !ok || r.Cmp(big.NewRat(1, 10)) != 0 {
+		t.Errorf("expected exactly 1/10, got %v", r)
+	}
+	b, err := json.Marshal(cells)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Price":1234567890.123456789,"Discount":0.1,"Empty":null}`; string(b) != want {
+		t.Errorf("unexpected JSON\n got: %s\nwant: %s", b, want)
+	}
+
+	if _, err := airtable.ParseDecimal("12,50"); err == nil {
+		t.Error("expected an error for an invalid decimal")
+	}
+	if err := json.Unmarshal([]byte(`"12.50"`), &cells.Price); err == nil {
+		t.Error("expected an error decoding a string")
+	}
+}
+
+func TestInvalidDecimalError(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL)
+	})
+	defer done()
+
+	type product struct {
+		airtable.Record
+		Fields struct {
+			Price airtable.Decimal
+		}
+	}
+	table := client.Table("Products")
+	record := product{}
+	record.Fields.Price = "1,234.50"
+	var decimalErr airtable.ErrInvalidDecimal
+	if err := table.Create(&record); !errors.As(err, &decimalErr) || decimalErr.Value != "1,234.50" {
+		t.Errorf("expected an ErrInvalidDecimal, got %v", err)
+	}
+	record.ID = "rec1"
+	if err := table.Update(&record); !errors.As(err, &decimalErr) {
+		t.Errorf("expected an ErrInvalidDecimal, got %v", err)
+	}
+}
+
+func TestRating(t *testing.T) {
+	var cells struct {
+		Stars, Empty airtable.Rating
+	}
+	if err := json.Unmarshal([]byte(`{"Stars": 4, "Empty": null}`), &cells); err != nil {
+		t.Fatal(err)
+	}
+	if cells.Stars != 4 || cells.Empty != 0 {
+		t.Errorf("unexpected cells %+v", cells)
+	}
+	if b, _ := json.Marshal(cells); string(b) != `{"Stars":4,"Empty":null}` {
+		t.Errorf("unexpected JSON %s", b)
+	}
+	if err := cells.Stars.Check(3); err == nil {
+		t.Error("expected 4 to be out of range for a max of 3")
+	}
+}
+
+func TestRatingRangeError(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL)
+	})
+	defer done()
+
+	type review struct {
+		airtable.Record
+		Fields struct {
+			Stars airtable.Rating
+		}
+	}
+	table := client.Table("Reviews")
+	record := review{}
+	record.Fields.Stars = 11
+	var rangeErr airtable.ErrRatingRange
+	if err := table.Create(&record); !errors.As(err, &rangeErr) || rangeErr.Value != 11 {
+		t.Errorf("expected an ErrRatingRange, got %v", err)
+	}
+}
+
+func TestCreatedByLastModifiedBy(t *testing.T) {
+	handler, bodies := recordBodies()
+	client, done := newTestClient(handler)
+	defer done()
+
+	type task struct {
+		airtable.Record
+		Fields struct {
+			Title    string
+			Author   airtable.CreatedBy
+			Modifier airtable.LastModifiedBy
+		}
+	}
+	record := task{}
+	err := json.Unmarshal([]byte(`{"id": "rec1", "fields": {
+		"Title": "a",
+		"Author": {"id": "usrA", "email": "a@example.com", "name": "A"},
+		"Modifier": {"id": "autB", "name": "Nightly cleanup", "type": "automation"}
+	}}`), &record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Fields.Author.Email != "a@example.com" || record.Fields.Author.Kind != airtable.ActorUser {
+		t.Errorf("unexpected author %+v", record.Fields.Author)
+	}
+	if record.Fields.Modifier.Kind != airtable.ActorAutomation || record.Fields.Modifier.Name != "Nightly cleanup" {
+		t.Errorf("unexpected modifier %+v", record.Fields.Modifier)
+	}
+
+	table := client.Table("Tasks")
+	if err := table.Update(&record); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"fields": {"Title":"a"}, "typecast": false}`; (*bodies)[0] != want {
+		t.Errorf("unexpected body\n got: %s\nwant: %s", (*bodies)[0], want)
+	}
+}
+
+func TestLastModifiedTime(t *testing.T) {
+	handler, bodies := recordBodies()
+	client, done := newTestClient(handler)
+	defer done()
+
+	type task struct {
+		airtable.Record
+		Fields struct {
+			Title   string
+			Changed airtable.LastModifiedTime
+			Never   airtable.LastModifiedTime
+		}
+	}
+	record := task{}
+	err := json.Unmarshal([]byte(`{"id": "rec1", "fields": {
+		"Title": "a", "Changed": "2020-01-02T03:04:05.000Z", "Never": null
+	}}`), &record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC); !record.Fields.Changed.Equal(want) || !record.Fields.Never.IsZero() {
+		t.Errorf("unexpected times %v, %v", record.Fields.Changed, record.Fields.Never)
+	}
+
+	table := client.Table("Tasks")
+	if err := table.Update(&record); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"fields": {"Title":"a"}, "typecast": false}`; (*bodies)[0] != want {
+		t.Errorf("unexpected body\n got: %s\nwant: %s", (*bodies)[0], want)
+	}
+}
+
+func TestAutoNumber(t *testing.T) {
+	handler, bodies := recordBodies()
+	client, done := newTestClient(handler)
+	defer done()
+
+	type ticket struct {
+		airtable.Record
+		Fields struct {
+			Title  string
+			Number airtable.AutoNumber
+		}
+	}
+	var records []ticket
+	err := json.Unmarshal([]byte(`[{"id": "rec1", "fields": {"Title": "a", "Number": 42}}]`), &records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records[0].Fields.Number != 42 {
+		t.Errorf("unexpected number %d", records[0].Fields.Number)
+	}
+
+	table := client.Table("Tickets")
+	if err := table.Create(&records[0]); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"fields": {"Title":"a"}, "typecast": false}`; (*bodies)[0] != want {
+		t.Errorf("unexpected body\n got: %s\nwant: %s", (*bodies)[0], want)
+	}
+}
+
+func TestRollupResult(t *testing.T) {
+	var cells struct {
+		Total, Names, Unique, Broken, Empty airtable.RollupResult
+	}
+	err := json.Unmarshal([]byte(`{
+		"Total": 12.5,
+		"Names": "a, b",
+		"Unique": ["a", 1],
+		"Broken": {"error": "#ERROR!"}
+	}`), &cells)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := cells.Total.Value(); !ok || v != 12.5 {
+		t.Errorf("unexpected total %v", v)
+	}
+	if v, ok := cells.Names.Value(); !ok || v != "a, b" {
+		t.Errorf("unexpected names %v", v)
+	}
+	if len(cells.Unique.Array) != 2 || cells.Unique.Array[0] != "a" {
+		t.Errorf("unexpected array %v", cells.Unique.Array)
+	}
+	if _, ok := cells.Broken.Value(); ok || *cells.Broken.Error != "#ERROR!" {
+		t.Errorf("expected an error, got %+v", cells.Broken)
+	}
+	if _, ok := cells.Empty.Value(); ok {
+		t.Error("expected no value for an empty rollup")
+	}
+	if err := json.Unmarshal([]byte(`true`), &cells.Total); err == nil {
+		t.Error("expected an error for a boolean")
+	}
+}
+
+func TestLookup(t *testing.T) {
+	var cells struct {
+		Mixed, Owners, Empty airtable.Lookup
+	}
+	err := json.Unmarshal([]byte(`{
+		"Mixed": ["a", 1, "b", 2.5, null],
+		"Owners": [{"id": "usrA", "email": "a@example.com", "name": "A"}]
+	}`), &cells)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cells.Mixed.Strings(); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("unexpected strings %v", got)
+	}
+	if got := cells.Mixed.Floats(); len(got) != 2 || got[0] != 1 || got[1] != 2.5 {
+		t.Errorf("unexpected floats %v", got)
+	}
+	if len(cells.Mixed.Raw()) != 5 || len(cells.Empty.Raw()) != 0 {
+		t.Errorf("unexpected raw values %s", cells.Mixed.Raw())
+	}
+	var owners []airtable.Collaborator
+	if err := cells.Owners.Decode(&owners); err != nil {
+		t.Fatal(err)
+	}
+	if len(owners) != 1 || owners[0].Email != "a@example.com" {
+		t.Errorf("unexpected owners %+v", owners)
+	}
+}
+
+func TestMultiSelect(t *testing.T) {
+	tags := airtable.MultiSelect{"b", "a"}
+	tags.Add("c", "a")
+	if !tags.Has("c") || len(tags) != 3 {
+		t.Errorf("unexpected tags after Add %v", tags)
+	}
+	tags.Remove("b", "z")
+	if tags.Has("b") || len(tags) != 2 {
+		t.Errorf("unexpected tags after Remove %v", tags)
+	}
+	union := tags.Union(airtable.MultiSelect{"d", "a"})
+	if want := (airtable.MultiSelect{"a", "c", "d"}); fmt.Sprint(union) != fmt.Sprint(want) {
+		t.Errorf("unexpected union %v, want %v", union, want)
+	}
+
+	b, err := json.Marshal(airtable.MultiSelect{"x", "y", "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `["x","y"]` {
+		t.Errorf("unexpected JSON %s", b)
+	}
+}
+
+func TestAttachmentThumbnails(t *testing.T) {
+	var cover airtable.Attachment
+	err := json.Unmarshal([]byte(`[{"id": "attA", "url": "https://example.com/a.png", "thumbnails": {
+		"small": {"url": "https://example.com/s.png", "width": 36, "height": 36},
+		"large": {"url": "https://example.com/l.png", "width": 512, "height": 512},
+		"full": {"url": "https://example.com/f.png", "width": 3000, "height": 2000}
+	}}]`), &cover)
+	if err != nil {
+		t.Fatal(err)
+	}
+	full := cover[0].Thumbnails.Full
+	if full.URL != "https://example.com/f.png" || full.Width != 3000 || full.Height != 2000 {
+		t.Errorf("unexpected full thumbnail %+v", full)
+	}
+	if cover[0].Thumbnails.Small.Width != 36 {
+		t.Errorf("unexpected thumbnails %+v", cover[0].Thumbnails)
+	}
+}
+
+func TestFormulaResult(t *testing.T) {
+	var cells struct {
+		Name, Total, Tags, Broken, Empty airtable.FormulaResult
+	}
+	err := json.Unmarshal([]byte(`{
+		"Name": "a",
+		"Total": 3,
+		"Tags": ["x", "y"],
+		"Broken": {"error": "#ERROR!"},
+		"Empty": null
+	}`), &cells)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := cells.Name.AsString(); !ok || s != "a" {
+		t.Errorf("unexpected name %+v", cells.Name)
+	}
+	if _, ok := cells.Name.AsFloat(); ok {
+		t.Error("expected the name not to be a number")
+	}
+	if n, ok := cells.Total.AsFloat(); !ok || n != 3 || string(cells.Total.Raw) != "3" {
+		t.Errorf("unexpected total %+v", cells.Total)
+	}
+	if tags, ok := cells.Tags.AsArray(); !ok || len(tags) != 2 {
+		t.Errorf("unexpected tags %+v", cells.Tags)
+	}
+	if _, ok := cells.Broken.Value(); ok || *cells.Broken.Error != "#ERROR!" {
+		t.Errorf("expected an error, got %+v", cells.Broken)
+	}
+	if _, ok := cells.Empty.Value(); ok {
+		t.Error("expected no value for an empty formula")
+	}
+
+	var odd airtable.FormulaResult
+	if err := json.Unmarshal([]byte(`{"weird": true}`), &odd); err == nil || string(odd.Raw) != `{"weird": true}` {
+		t.Errorf("expected an error keeping the raw JSON, got %v, %s", err, odd.Raw)
+	}
+	if err := json.Unmarshal([]byte(`true`), &odd); err == nil {
+		t.Error("expected an error for a boolean")
+	}
+}
+
+func TestSyncSource(t *testing.T) {
+	handler, bodies := recordBodies()
+	client, done := newTestClient(handler)
+	defer done()
+
+	type synced struct {
+		airtable.Record
+		Fields struct {
+			Title  string
+			Source airtable.SyncSource
+			Origin airtable.SyncSource
+		}
+	}
+	record := synced{}
+	err := json.Unmarshal([]byte(`{"id": "rec1", "fields": {
+		"Title": "a",
+		"Source": {"id": "selA", "name": "Sales base", "color": "blueLight2"},
+		"Origin": "Jira"
+	}}`), &record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Fields.Source.ID != "selA" || record.Fields.Source.Name != "Sales base" || record.Fields.Origin.Name != "Jira" {
+		t.Errorf("unexpected sources %+v", record.Fields)
+	}
+
+	table := client.Table("Synced")
+	if err := table.Update(&record); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"fields": {"Title":"a"}, "typecast": false}`; (*bodies)[0] != want {
+		t.Errorf("unexpected body\n got: %s\nwant: %s", (*bodies)[0], want)
+	}
+}
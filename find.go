@@ -0,0 +1,46 @@
+package airtable
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// ErrNoMatch is returned by First when no record matches the formula.
+type ErrNoMatch struct {
+	Table   string
+	Formula string
+}
+
+func (e ErrNoMatch) Error() string {
+	return fmt.Sprintf("airtable: no record in %s matches %s", e.Table, e.Formula)
+}
+
+// First stores the first record in the table matching the formula in the
+// object pointed to by recordPtr, or returns an ErrNoMatch if there
+// isn't one. Only one record is requested. For example:
+//
+//	err := table.First(&user, fmt.Sprintf("{Email} = %q", email))
+//
+// recordPtr has the same requirements as in Create.
+func (t *Table) First(recordPtr interface{}, formula string) error {
+	return t.FirstContext(context.Background(), recordPtr, formula)
+}
+
+// FirstContext is like First but the request is bound to ctx.
+func (t *Table) FirstContext(ctx context.Context, recordPtr interface{}, formula string) error {
+	if err := t.argError(checkRecordArg(recordPtr)); err != nil {
+		return err
+	}
+	record := reflect.ValueOf(recordPtr).Elem()
+	list := reflect.New(reflect.SliceOf(record.Type()))
+	options := &Options{Filter: formula, MaxRecords: 1}
+	if err := t.ListContext(ctx, list.Interface(), options); err != nil {
+		return err
+	}
+	if list.Elem().Len() == 0 {
+		return ErrNoMatch{Table: t.name, Formula: formula}
+	}
+	record.Set(list.Elem().Index(0))
+	return nil
+}
@@ -0,0 +1,38 @@
+package airtable_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestFirst(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("maxRecords") != "1" {
+			t.Errorf("expected a single record to be requested, got %s", r.URL.RawQuery)
+		}
+		if query.Get("filterByFormula") == `{Title} = "a"` {
+			fmt.Fprint(w, `{"records": [{"id": "rec1", "fields": {"Title": "a"}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"records": []}`)
+	})
+	defer done()
+
+	table := client.Table("Tasks")
+	task := taskRecord{}
+	if err := table.First(&task, `{Title} = "a"`); err != nil {
+		t.Fatal(err)
+	}
+	if task.ID != "rec1" || task.Fields.Title != "a" {
+		t.Errorf("unexpected record %+v", task)
+	}
+
+	err := table.First(&task, `{Title} = "b"`)
+	if _, ok := err.(airtable.ErrNoMatch); !ok {
+		t.Errorf("expected ErrNoMatch, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package airtable
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultFleetConcurrency is how many bases a Fleet works on at once
+// when its Concurrency isn't set.
+var DefaultFleetConcurrency = 4
+
+// Fleet runs the same operation across many bases, such as one base per
+// customer, with bounded concurrency.
+//
+// - Client: template for the client used for each base. Everything but
+// BaseID is copied from it. If the template doesn't set a Limiter, each
+// base is paced by the limiter shared by every client for that base, so
+// the fleet respects Airtable's per-base rate limit; a Limiter set on
+// the template is shared by every base.
+//
+// - BaseIDs: the bases to run operations on.
+//
+// - Concurrency: how many bases to work on at once. Defaults to
+// DefaultFleetConcurrency.
+type Fleet struct {
+	Client      Client
+	BaseIDs     []string
+	Concurrency int
+
+	mu      sync.Mutex
+	clients map[string]*Client
+}
+
+// ErrFleet is returned when an operation fails on some of the bases in
+// a fleet. Errors holds the error for each base that failed; the
+// operation still ran on every other base.
+type ErrFleet struct {
+	Errors map[string]error
+	Bases  int
+}
+
+func (e ErrFleet) Error() string {
+	ids := make([]string, 0, len(e.Errors))
+	for id := range e.Errors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	msgs := make([]string, len(ids))
+	for i, id := range ids {
+		msgs[i] = fmt.Sprintf("%s: %s", id, e.Errors[id])
+	}
+	return fmt.Sprintf("airtable: %d of %d bases failed: %s",
+		len(e.Errors), e.Bases, strings.Join(msgs, "; "))
+}
+
+// Run calls fn with a client for each base, working on up to
+// Concurrency bases at once, and waits for every call to return. If any
+// of them fail the error is an ErrFleet.
+func (f *Fleet) Run(ctx context.Context, fn func(ctx context.Context, client *Client) error) error {
+	concurrency := f.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultFleetConcurrency
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs = map[string]error{}
+		sem  = make(chan struct{}, concurrency)
+	)
+	for _, id := range f.BaseIDs {
+		client := f.client(id)
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(ctx, client); err != nil {
+				mu.Lock()
+				errs[id] = err
+				mu.Unlock()
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return ErrFleet{Errors: errs, Bases: len(f.BaseIDs)}
+	}
+	return nil
+}
+
+// Schemas fetches the schema of every base, for example to check that
+// they all have the tables and fields an application needs. Schemas are
+// returned for the bases that didn't fail even if the error is non-nil.
+func (f *Fleet) Schemas(ctx context.Context) (map[string]*BaseSchema, error) {
+	var (
+		mu      sync.Mutex
+		schemas = map[string]*BaseSchema{}
+	)
+	err := f.Run(ctx, func(ctx context.Context, client *Client) error {
+		schema, err := client.Schema(ctx)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		schemas[client.BaseID] = schema
+		mu.Unlock()
+		return nil
+	})
+	return schemas, err
+}
+
+// client returns the client for the base, making it the first time.
+func (f *Fleet) client(baseID string) *Client {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if client, ok := f.clients[baseID]; ok {
+		return client
+	}
+	if f.clients == nil {
+		f.clients = map[string]*Client{}
+	}
+
+	client := f.Client
+	client.BaseID = baseID
+	client.throttle = nil
+	client.schema = nil
+	client.linkCache = nil
+	client.setUp = 0
+	f.clients[baseID] = &client
+	return &client
+}
@@ -0,0 +1,34 @@
+//go:build go1.18
+
+package airtable
+
+import (
+	"context"
+	"sync"
+)
+
+// FleetList lists the records of the table in every base of the fleet.
+// Each base gets its own copy of options. Records are returned for the
+// bases that didn't fail even if the error, an ErrFleet, is non-nil.
+func FleetList[T any](ctx context.Context, f *Fleet, table string, options *Options) (map[string][]RecordOf[T], error) {
+	var (
+		mu      sync.Mutex
+		records = map[string][]RecordOf[T]{}
+	)
+	err := f.Run(ctx, func(ctx context.Context, client *Client) error {
+		opts := Options{}
+		if options != nil {
+			opts = *options
+		}
+		list := []RecordOf[T]{}
+		t := client.Table(table)
+		if err := t.ListContext(ctx, &list, &opts); err != nil {
+			return err
+		}
+		mu.Lock()
+		records[client.BaseID] = list
+		mu.Unlock()
+		return nil
+	})
+	return records, err
+}
@@ -0,0 +1,50 @@
+//go:build go1.18
+
+package airtable_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestFleetList(t *testing.T) {
+	template, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		base := strings.Split(r.URL.Path, "/")[2]
+		if base == "appBROKEN" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": "NOT_FOUND"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"records": [{"id": "rec1", "fields": {"Title": %q}}]}`, base)
+	})
+	defer done()
+
+	fleet := &airtable.Fleet{
+		Client:      *template,
+		BaseIDs:     []string{"appA", "appB", "appBROKEN"},
+		Concurrency: 2,
+	}
+	type task struct{ Title string }
+	records, err := airtable.FleetList[task](context.Background(), fleet, "Tasks", nil)
+
+	fleetErr, ok := err.(airtable.ErrFleet)
+	if !ok {
+		t.Fatalf("expected an ErrFleet, got %v", err)
+	}
+	if len(fleetErr.Errors) != 1 || fleetErr.Errors["appBROKEN"] == nil {
+		t.Errorf("expected only appBROKEN to fail, got %v", fleetErr.Errors)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected records from 2 bases, got %d", len(records))
+	}
+	for _, id := range []string{"appA", "appB"} {
+		if got := records[id]; len(got) != 1 || got[0].Fields.Title != id {
+			t.Errorf("unexpected records for %s: %+v", id, got)
+		}
+	}
+}
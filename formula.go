@@ -0,0 +1,59 @@
+package airtable
+
+import (
+	"fmt"
+	"strings"
+)
+
+// formulaString quotes s as a string literal for use in an Airtable
+// formula.
+func formulaString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// formulaField references the field named name in an Airtable formula.
+func formulaField(name string) string {
+	return "{" + strings.Replace(name, "}", `\}`, -1) + "}"
+}
+
+// formulaOr combines formulas so that any of them must match.
+func formulaOr(formulas []string) string {
+	if len(formulas) == 1 {
+		return formulas[0]
+	}
+	return fmt.Sprintf("OR(%s)", strings.Join(formulas, ", "))
+}
+
+// recordIDFormula matches the records with any of the given IDs.
+func recordIDFormula(ids []string) string {
+	formulas := make([]string, len(ids))
+	for i, id := range ids {
+		formulas[i] = "RECORD_ID() = " + formulaString(id)
+	}
+	return formulaOr(formulas)
+}
+
+// isRecordID reports whether s looks like an Airtable record ID, e.g.
+// "recXXXXXXXXXXXXXX".
+func isRecordID(s string) bool {
+	return isID("rec", s)
+}
+
+// isFieldID reports whether s looks like an Airtable field ID, e.g.
+// "fldXXXXXXXXXXXXXX".
+func isFieldID(s string) bool {
+	return isID("fld", s)
+}
+
+func isID(prefix, s string) bool {
+	if len(s) != 17 || !strings.HasPrefix(s, prefix) {
+		return false
+	}
+	for _, c := range s[3:] {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+//go:build go1.23
+
+package airtable
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+)
+
+// Records returns an iterator over the records of table matching
+// options, for use with range:
+//
+//	for task, err := range airtable.Records[Task](table, nil) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(task.Fields.Title)
+//	}
+//
+// T is a record type, like the element type of the slice passed to
+// List. Pages are fetched as the loop gets to them, and fetching stops
+// as soon as the loop exits. If a page can't be fetched the iterator
+// yields the error, an ErrListInterrupted if the failure was temporary
+// as in List, and stops. options isn't modified.
+func Records[T any](table Table, options *Options) iter.Seq2[*T, error] {
+	return RecordsContext[T](context.Background(), table, options)
+}
+
+// RecordsContext is like Records but the requests are bound to ctx.
+func RecordsContext[T any](ctx context.Context, table Table, options *Options) iter.Seq2[*T, error] {
+	if err := table.argError(checkListArg(&[]T{})); err != nil {
+		return func(yield func(*T, error) bool) {
+			yield(nil, err)
+		}
+	}
+	return records[T](ctx, table, options)
+}
+
+// RecordEnvelope is a record of any table, with its fields left as JSON
+// to be decoded as needed. It's what Table.Records yields when there's
+// no struct for the table's records.
+type RecordEnvelope struct {
+	Record
+	Fields map[string]json.RawMessage
+}
+
+// Records is like the package function Records but yields the records
+// as RecordEnvelopes, so it works for any table:
+//
+//	for record, err := range table.Records(nil) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(record.ID, string(record.Fields["Title"]))
+//	}
+//
+// Options.Fields, Options.Sort and Options.SortBy take Airtable field names.
+func (t *Table) Records(options *Options) iter.Seq2[RecordEnvelope, error] {
+	return t.RecordsContext(context.Background(), options)
+}
+
+// RecordsContext is like Records but the requests are bound to ctx.
+func (t *Table) RecordsContext(ctx context.Context, options *Options) iter.Seq2[RecordEnvelope, error] {
+	return func(yield func(RecordEnvelope, error) bool) {
+		for record, err := range records[RecordEnvelope](ctx, *t, options) {
+			if err != nil {
+				yield(RecordEnvelope{}, err)
+				return
+			}
+			if !yield(*record, nil) {
+				return
+			}
+		}
+	}
+}
+
+// records iterates over the records of table without checking T, so it
+// can be used for records whose Fields aren't a struct.
+func records[T any](ctx context.Context, table Table, options *Options) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		opts := Options{}
+		if options != nil {
+			opts = *options
+		}
+		opts.setType(getRecordType(&[]T{}))
+		if err := table.argError(opts.check()); err != nil {
+			yield(nil, err)
+			return
+		}
+		for {
+			body, err := table.client.requestPage(withStreamedBody(ctx), table.makePath(""), &opts)
+			if err != nil {
+				body.close()
+				yield(nil, listError(opts.Offset, err))
+				return
+			}
+			// each page gets a new slice since the caller may hold on
+			// to the records.
+			var page []T
+			offset, err := table.client.newListDecoder(&page).read(body)
+			body.close()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for i := range page {
+				if !yield(&page[i], nil) {
+					return
+				}
+			}
+			if offset == "" {
+				return
+			}
+			opts.Offset = offset
+		}
+	}
+}
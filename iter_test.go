@@ -0,0 +1,81 @@
+//go:build go1.23
+
+package airtable_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestRecords(t *testing.T) {
+	pages := 0
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		switch r.URL.Query().Get("offset") {
+		case "":
+			fmt.Fprint(w, recordsResponse(0, 2, "page2"))
+		case "page2":
+			fmt.Fprint(w, recordsResponse(2, 2, "page3"))
+		default:
+			fmt.Fprint(w, recordsResponse(4, 1, ""))
+		}
+	})
+	defer done()
+	table := client.Table("Tasks")
+
+	var ids []string
+	for record, err := range airtable.Records[taskRecord](table, nil) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, record.ID)
+	}
+	if len(ids) != 5 || pages != 3 {
+		t.Errorf("expected 5 records from 3 pages, got %v from %d", ids, pages)
+	}
+
+	// breaking out of the loop stops pagination.
+	pages = 0
+	for record, err := range airtable.Records[taskRecord](table, nil) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record.ID == "rec1" {
+			break
+		}
+	}
+	if pages != 1 {
+		t.Errorf("expected to stop after the first page, fetched %d", pages)
+	}
+}
+
+func TestTableRecords(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "" {
+			fmt.Fprint(w, `{"offset": "page2", "records": [{"id": "rec1", "fields": {"Title": "a"}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"records": [{"id": "rec2", "fields": {"Title": "b", "Done": true}}]}`)
+	})
+	defer done()
+	client.KeepFieldsPresent = true
+	table := client.Table("Tasks")
+
+	var got []string
+	options := &airtable.Options{Fields: []string{"Title", "Done"}}
+	for record, err := range table.Records(options) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, fmt.Sprintf("%s=%s", record.ID, record.Fields["Title"]))
+		if record.ID == "rec2" && !record.FieldsPresent()["Done"] {
+			t.Errorf("expected Done to be present in %+v", record)
+		}
+	}
+	if fmt.Sprint(got) != `[rec1="a" rec2="b"]` {
+		t.Errorf("unexpected records %v", got)
+	}
+}
@@ -0,0 +1,186 @@
+package airtable
+
+// JSONSchemaDraft is the JSON Schema dialect produced by
+// TableSchema.JSONSchema.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is the subset of a JSON Schema document needed to describe
+// the fields of an Airtable record.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	ID                   string                 `json:"$id,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	ReadOnly             bool                   `json:"readOnly,omitempty"`
+}
+
+// JSONSchemas converts every table in the base to a JSON Schema
+// document, keyed by table name.
+func (s *BaseSchema) JSONSchemas() map[string]*JSONSchema {
+	schemas := make(map[string]*JSONSchema, len(s.Tables))
+	for i := range s.Tables {
+		schemas[s.Tables[i].Name] = s.Tables[i].JSONSchema()
+	}
+	return schemas
+}
+
+// JSONSchema converts the table to a JSON Schema document describing the
+// "fields" object of its records, with one property per field, keyed by
+// field name. Airtable leaves empty cells out of records, so no property
+// is required. Computed fields are marked readOnly.
+func (t *TableSchema) JSONSchema() *JSONSchema {
+	closed := false
+	schema := &JSONSchema{
+		Schema:               JSONSchemaDraft,
+		ID:                   t.ID,
+		Title:                t.Name,
+		Description:          t.Description,
+		Type:                 "object",
+		Properties:           make(map[string]*JSONSchema, len(t.Fields)),
+		AdditionalProperties: &closed,
+	}
+	for _, f := range t.Fields {
+		property := fieldJSONSchema(f.Type, f.Options)
+		property.Title = f.Name
+		property.Description = f.Description
+		property.ReadOnly = isComputedFieldType(f.Type)
+		schema.Properties[f.Name] = property
+	}
+	return schema
+}
+
+// fieldJSONSchema describes the values of a field of the given type.
+func fieldJSONSchema(typ string, options *FieldOptions) *JSONSchema {
+	if options == nil {
+		options = &FieldOptions{}
+	}
+	switch typ {
+	case "singleLineText", "multilineText", "richText", "phoneNumber":
+		return &JSONSchema{Type: "string"}
+	case "email":
+		return &JSONSchema{Type: "string", Format: "email"}
+	case "url":
+		return &JSONSchema{Type: "string", Format: "uri"}
+	case "number", "currency", "percent", "duration":
+		return &JSONSchema{Type: "number"}
+	case "rating":
+		s := &JSONSchema{Type: "integer", Minimum: float64Ptr(0)}
+		if options.Max != 0 {
+			s.Maximum = float64Ptr(float64(options.Max))
+		}
+		return s
+	case "autoNumber", "count":
+		return &JSONSchema{Type: "integer"}
+	case "checkbox":
+		return &JSONSchema{Type: "boolean"}
+	case "date":
+		return &JSONSchema{Type: "string", Format: "date"}
+	case "dateTime", "createdTime", "lastModifiedTime":
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	case "singleSelect":
+		return &JSONSchema{Type: "string", Enum: choiceNames(options.Choices)}
+	case "multipleSelects":
+		return &JSONSchema{
+			Type:  "array",
+			Items: &JSONSchema{Type: "string", Enum: choiceNames(options.Choices)},
+		}
+	case "multipleRecordLinks":
+		return &JSONSchema{
+			Type:  "array",
+			Items: &JSONSchema{Type: "string", Pattern: "^rec[a-zA-Z0-9]{14}$"},
+		}
+	case "multipleAttachments":
+		return &JSONSchema{
+			Type: "array",
+			Items: &JSONSchema{
+				Type: "object",
+				Properties: map[string]*JSONSchema{
+					"id":       {Type: "string"},
+					"url":      {Type: "string", Format: "uri"},
+					"filename": {Type: "string"},
+					"size":     {Type: "number"},
+					"type":     {Type: "string"},
+				},
+			},
+		}
+	case "singleCollaborator", "createdBy", "lastModifiedBy":
+		return collaboratorJSONSchema()
+	case "multipleCollaborators":
+		return &JSONSchema{Type: "array", Items: collaboratorJSONSchema()}
+	case "barcode":
+		return &JSONSchema{
+			Type: "object",
+			Properties: map[string]*JSONSchema{
+				"text": {Type: "string"},
+				"type": {Type: "string"},
+			},
+		}
+	case "button":
+		return &JSONSchema{
+			Type: "object",
+			Properties: map[string]*JSONSchema{
+				"label": {Type: "string"},
+				"url":   {Type: "string", Format: "uri"},
+			},
+		}
+	case "formula", "rollup":
+		if options.Result != nil {
+			return fieldJSONSchema(options.Result.Type, options.Result.Options)
+		}
+	case "multipleLookupValues":
+		items := &JSONSchema{}
+		if options.Result != nil {
+			items = fieldJSONSchema(options.Result.Type, options.Result.Options)
+		}
+		return &JSONSchema{Type: "array", Items: items}
+	}
+	// unknown or untyped fields can hold anything.
+	return &JSONSchema{}
+}
+
+func collaboratorJSONSchema() *JSONSchema {
+	return &JSONSchema{
+		Type: "object",
+		Properties: map[string]*JSONSchema{
+			"id":    {Type: "string"},
+			"email": {Type: "string", Format: "email"},
+			"name":  {Type: "string"},
+		},
+	}
+}
+
+func choiceNames(choices []FieldChoice) []string {
+	if len(choices) == 0 {
+		return nil
+	}
+	names := make([]string, len(choices))
+	for i, c := range choices {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// isComputedFieldType reports whether fields of the given type are
+// computed by Airtable and can't be written to.
+func isComputedFieldType(typ string) bool {
+	switch typ {
+	case "formula", "rollup", "multipleLookupValues", "count",
+		"autoNumber", "createdTime", "lastModifiedTime",
+		"createdBy", "lastModifiedBy", "button":
+		return true
+	}
+	return false
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
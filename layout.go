@@ -0,0 +1,80 @@
+package airtable
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	timePtrType = reflect.TypeOf(&time.Time{})
+)
+
+// layoutCodec returns a codec for a time.Time or *time.Time field with a
+// layout tag, which gives the time.Parse layout the field's cells are
+// in. Bases mix date, date-time and text fields holding timestamps, so
+// each field can have its own layout:
+//
+//	Fields struct {
+//		Due     time.Time  `layout:"2006-01-02"`
+//		Started *time.Time `json:"Started At" layout:"02/01/2006 15:04"`
+//	}
+//
+// Zero times and nil pointers are left out of requests, like any field
+// that isn't sent: Update leaves the cell alone and Replace clears it.
+// To clear the cell with Update too, tag the field clearable, and zero
+// times are sent as null:
+//
+//	Due time.Time `layout:"2006-01-02" airtable:",clearable"`
+//
+// Fields without a layout tag are encoded and decoded as RFC 3339, as
+// encoding/json does.
+func layoutCodec(f reflect.StructField) (Codec, bool) {
+	layout := f.Tag.Get("layout")
+	if layout == "" || (f.Type != timeType && f.Type != timePtrType) {
+		return Codec{}, false
+	}
+	ptr := f.Type == timePtrType
+	var zero interface{}
+	if parseFieldTag(f).clearable {
+		zero = json.RawMessage("null")
+	}
+	return Codec{
+		Marshal: func(v interface{}) (interface{}, error) {
+			var t time.Time
+			if ptr {
+				p := v.(*time.Time)
+				if p == nil {
+					return zero, nil
+				}
+				t = *p
+			} else {
+				t = v.(time.Time)
+			}
+			if t.IsZero() {
+				return zero, nil
+			}
+			return t.Format(layout), nil
+		},
+		Unmarshal: func(data []byte, v interface{}) error {
+			var s *string
+			if err := json.Unmarshal(data, &s); err != nil {
+				return err
+			}
+			if s == nil || *s == "" {
+				return nil
+			}
+			t, err := time.Parse(layout, *s)
+			if err != nil {
+				return err
+			}
+			if ptr {
+				*v.(**time.Time) = &t
+			} else {
+				*v.(*time.Time) = t
+			}
+			return nil
+		},
+	}, true
+}
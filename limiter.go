@@ -0,0 +1,145 @@
+package airtable
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/ratelimit"
+)
+
+var baseLimiters = struct {
+	sync.Mutex
+	limiters map[string]ratelimit.Limiter
+}{limiters: map[string]ratelimit.Limiter{}}
+
+// limiterFor returns the limiter shared by the clients in this process
+// that don't have one of their own, for the base.
+func limiterFor(baseID string) ratelimit.Limiter {
+	baseLimiters.Lock()
+	defer baseLimiters.Unlock()
+	limiter, ok := baseLimiters.limiters[baseID]
+	if !ok {
+		limiter = RateLimiter(DefaultRateLimit)
+		baseLimiters.limiters[baseID] = limiter
+	}
+	return limiter
+}
+
+// BaseLimiter paces requests to a base. Unlike Limiter, which only
+// knows about the requests made through it, a BaseLimiter can keep its
+// state outside the process (e.g. in Redis or memcached) so that many
+// processes share one per-base quota. When a client has a BaseLimiter,
+// it's used instead of the client's Limiter.
+type BaseLimiter interface {
+	// Wait blocks until a request to the base may be made, or returns
+	// an error if ctx is done or the limiter's backend fails.
+	Wait(ctx context.Context, baseID string) error
+}
+
+// Counter is an atomic counter store shared by every process that needs
+// to coordinate, which is all SharedLimiter needs from a backend. With
+// Redis, for example, Incr is an INCR followed by an EXPIRE in one
+// transaction:
+//
+//	func (c redisCounter) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+//		pipe := c.rdb.TxPipeline()
+//		n := pipe.Incr(ctx, key)
+//		pipe.Expire(ctx, key, ttl)
+//		_, err := pipe.Exec(ctx)
+//		return n.Val(), err
+//	}
+type Counter interface {
+	// Incr adds one to the counter at key and returns the new value.
+	// A counter that doesn't exist starts at zero and should be
+	// removed by the store after ttl.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+}
+
+// SharedLimiter is a BaseLimiter that coordinates through a Counter. It
+// splits each second into Rate slots and lets one request through per
+// slot, across every process sharing the Counter, so requests are
+// spread evenly instead of bursting at the start of each second.
+//
+// - Counter: store shared by the processes. Required.
+//
+// - Rate: requests per second allowed per base. Defaults to 5, which is
+// Airtable's per-base limit.
+//
+// - Prefix: prepended to the keys in the Counter. Defaults to
+// "airtable:ratelimit:".
+type SharedLimiter struct {
+	Counter Counter
+	Rate    int
+	Prefix  string
+}
+
+// Wait blocks until this process wins a slot for the base.
+func (l *SharedLimiter) Wait(ctx context.Context, baseID string) error {
+	rate, prefix := l.Rate, l.Prefix
+	if rate <= 0 {
+		rate = 5
+	}
+	if prefix == "" {
+		prefix = "airtable:ratelimit:"
+	}
+	slot := time.Second / time.Duration(rate)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		now := time.Now()
+		current := now.Truncate(slot)
+		key := fmt.Sprintf("%s%s:%d", prefix, baseID, current.UnixNano()/int64(slot))
+		n, err := l.Counter.Incr(ctx, key, 2*time.Second)
+		if err != nil {
+			return err
+		}
+		if n == 1 {
+			return nil
+		}
+		// someone else got this slot; try again in the next one.
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(current.Add(slot).Sub(now)):
+		}
+	}
+}
+
+// MemoryCounter is a Counter kept in memory. It only coordinates within
+// a single process, which makes it useful for tests and as a fallback.
+type MemoryCounter struct {
+	mu       sync.Mutex
+	counters map[string]memoryCount
+}
+
+type memoryCount struct {
+	n       int64
+	expires time.Time
+}
+
+// Incr adds one to the counter at key.
+func (m *MemoryCounter) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if m.counters == nil {
+		m.counters = map[string]memoryCount{}
+	}
+	for k, c := range m.counters {
+		if now.After(c.expires) {
+			delete(m.counters, k)
+		}
+	}
+	c := m.counters[key]
+	if c.n == 0 {
+		c.expires = now.Add(ttl)
+	}
+	c.n++
+	m.counters[key] = c
+	return c.n, nil
+}
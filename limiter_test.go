@@ -0,0 +1,79 @@
+package airtable_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestSharedLimiter(t *testing.T) {
+	// two limiters sharing a counter behave like two processes sharing
+	// a Redis instance.
+	counter := &airtable.MemoryCounter{}
+	limiters := []*airtable.SharedLimiter{
+		{Counter: counter, Rate: 100},
+		{Counter: counter, Rate: 100},
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(l *airtable.SharedLimiter) {
+			defer wg.Done()
+			if err := l.Wait(context.Background(), "appTEST"); err != nil {
+				t.Error(err)
+			}
+		}(limiters[i%2])
+	}
+	wg.Wait()
+
+	// 6 requests need 6 distinct 10ms slots.
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("expected requests to be spread over slots, took %s", elapsed)
+	}
+}
+
+func TestSharedLimiterCanceled(t *testing.T) {
+	counter := &airtable.MemoryCounter{}
+	limiter := &airtable.SharedLimiter{Counter: counter, Rate: 1}
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := limiter.Wait(ctx, "appTEST"); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if err := limiter.Wait(ctx, "appTEST"); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestDefaultLimiterPerBase(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "rec1", "fields": {}}`)
+	}))
+	defer server.Close()
+
+	clients := []*airtable.Client{
+		{APIKey: "keyTEST", BaseID: "appA", RootURL: server.URL},
+		{APIKey: "keyTEST", BaseID: "appA", RootURL: server.URL},
+		{APIKey: "keyTEST", BaseID: "appB", RootURL: server.URL},
+	}
+	for _, client := range clients {
+		table := client.Table("Tasks")
+		if err := table.Get("rec1", &taskRecord{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if clients[0].Limiter != clients[1].Limiter {
+		t.Error("expected clients for the same base to share a limiter")
+	}
+	if clients[0].Limiter == clients[2].Limiter {
+		t.Error("expected clients for different bases to have their own limiters")
+	}
+}
@@ -0,0 +1,355 @@
+package airtable
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+)
+
+// linkBatchSize is the number of linked records requested at once. It
+// keeps the filter formula well under the API's URL length limit.
+const linkBatchSize = 100
+
+// LinkBinding describes the table a RecordLink field points to and how
+// to find records in it by a natural key instead of a record ID.
+//
+// - Table: the linked table.
+//
+// - KeyField: name of the field in the linked table that holds the
+// natural key, e.g. "Email" or "SKU". This is the Airtable field name,
+// since there's no record struct for the linked table to look it up in.
+//
+// - CreateMissing: create a record in the linked table, with only
+// KeyField set, for keys that don't match an existing record. If this
+// is false, unmatched keys cause Create and Update to fail.
+type LinkBinding struct {
+	Table         Table
+	KeyField      string
+	CreateMissing bool
+}
+
+// ErrLinkNotFound is returned by Create and Update when a RecordLink
+// field contains a natural key that doesn't match any record in the
+// linked table and the binding doesn't allow creating it.
+type ErrLinkNotFound struct {
+	Field string
+	Key   string
+}
+
+func (e ErrLinkNotFound) Error() string {
+	return fmt.Sprintf("airtable: no linked record for %s with key %q", e.Field, e.Key)
+}
+
+// BindLink lets the RecordLink field named field (the name of the field
+// in the record struct, like in Options.Sort) contain natural keys in
+// addition to record IDs. Before a record is sent by Create or Update,
+// every value in the field that isn't a record ID is looked up in the
+// linked table by binding.KeyField and replaced with the matching
+// record ID, both in the request and in the record itself.
+func (t *Table) BindLink(field string, binding LinkBinding) {
+	if t.links == nil {
+		t.links = map[string]LinkBinding{}
+	}
+	t.links[field] = binding
+}
+
+// resolveLinks replaces natural keys in the bound RecordLink fields of
+// the record pointed to by recordPtr with record IDs.
+func (t *Table) resolveLinks(ctx context.Context, recordPtr interface{}) error {
+	if len(t.links) == 0 {
+		return nil
+	}
+	fields := reflect.ValueOf(recordPtr).Elem().FieldByName("Fields")
+	if fields.Kind() != reflect.Struct {
+		return nil
+	}
+	for name, binding := range t.links {
+		f := fields.FieldByName(name)
+		if !f.IsValid() {
+			continue
+		}
+		if f.Kind() != reflect.Slice || f.Type().Elem().Kind() != reflect.String {
+			return t.argError(ErrInvalidArg{Arg: "recordPtr", Reason: fmt.Sprintf("has linked field %s that must be a RecordLink, got %s", name, f.Type())})
+		}
+
+		var keys []string
+		for i := 0; i < f.Len(); i++ {
+			if v := f.Index(i).String(); !isRecordID(v) {
+				keys = append(keys, v)
+			}
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		ids, err := binding.resolve(ctx, name, keys)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < f.Len(); i++ {
+			v := f.Index(i)
+			if id, ok := ids[v.String()]; ok {
+				v.SetString(id)
+			}
+		}
+	}
+	return nil
+}
+
+// resolve maps each key to the ID of the linked record, creating records
+// for missing keys if the binding allows it.
+func (b LinkBinding) resolve(ctx context.Context, field string, keys []string) (map[string]string, error) {
+	ids, err := b.lookup(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if _, ok := ids[key]; ok {
+			continue
+		}
+		if !b.CreateMissing {
+			return nil, ErrLinkNotFound{Field: field, Key: key}
+		}
+		id, err := b.Table.createFields(ctx, Fields{b.KeyField: key})
+		if err != nil {
+			return nil, err
+		}
+		ids[key] = id
+	}
+	return ids, nil
+}
+
+// lookup finds the IDs of the records in the linked table matching
+// keys. Keys without a match are missing from the result.
+func (b LinkBinding) lookup(ctx context.Context, keys []string) (map[string]string, error) {
+	formulas := make([]string, len(keys))
+	for i, key := range keys {
+		formulas[i] = fmt.Sprintf("%s = %s", formulaField(b.KeyField), formulaString(key))
+	}
+	query := url.Values{
+		"filterByFormula": {formulaOr(formulas)},
+		"fields[]":        {b.KeyField},
+	}
+
+	ids := map[string]string{}
+	for {
+		bytes, err := b.Table.client.RequestContext(ctx, "GET", b.Table.makePath(""), query)
+		if err != nil {
+			return nil, err
+		}
+		page := struct {
+			Records []struct {
+				ID     string
+				Fields map[string]interface{}
+			}
+			Offset string
+		}{}
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return nil, err
+		}
+		for _, record := range page.Records {
+			key := formatCell(record.Fields[b.KeyField])
+			if _, ok := ids[key]; !ok {
+				ids[key] = record.ID
+			}
+		}
+		if page.Offset == "" {
+			break
+		}
+		query.Set("offset", page.Offset)
+	}
+	return ids, nil
+}
+
+// createFields creates a record from a map of Airtable field names to
+// values and returns the ID of the new record.
+func (t *Table) createFields(ctx context.Context, fields Fields) (string, error) {
+	if err := t.validateFieldSelects(ctx, fields); err != nil {
+		return "", err
+	}
+	b, err := t.fieldsBody(fields)
+	if err != nil {
+		return "", err
+	}
+	res, err := t.client.RequestWithBodyContext(ctx, "POST", t.makePath(""), Options{}, bytes.NewReader(b))
+	if err != nil {
+		return "", err
+	}
+	created := Record{}
+	if err := json.Unmarshal(res, &created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// formatCell turns a decoded cell value into the string a formula
+// comparison against it would match.
+func formatCell(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// LinkedFrom finds the records in this table whose linked record field
+// named linkField (the Airtable field name) links to the record with ID
+// recordID, and appends them to the slice pointed to by listPtr, which
+// has the same requirements as in List.
+//
+// Formulas only see the primary field values of linked records, not
+// their IDs, so a formula like FIND(name, ARRAYJOIN({Link})) can both
+// miss records and match the wrong ones. LinkedFrom uses such a formula
+// to narrow down the records it asks for, if the schema says which
+// table linkField links to and the linked record's primary value is
+// text, and otherwise asks for every record with links in linkField.
+// Either way, it keeps only the records whose links include recordID.
+func (t *Table) LinkedFrom(linkField, recordID string, listPtr interface{}) error {
+	return t.LinkedFromContext(context.Background(), linkField, recordID, listPtr)
+}
+
+// LinkedFromContext is like LinkedFrom but the requests are bound to
+// ctx.
+func (t *Table) LinkedFromContext(ctx context.Context, linkField, recordID string, listPtr interface{}) error {
+	if err := t.argError(checkListArg(listPtr)); err != nil {
+		return err
+	}
+	filter, err := t.linkedFromFormula(ctx, linkField, recordID)
+	if err != nil {
+		return err
+	}
+
+	// each page is listed into found, and its matching records are
+	// copied to the list before found is emptied for the next page.
+	list := reflect.ValueOf(listPtr).Elem()
+	original := list.Len()
+	found := reflect.New(list.Type())
+	err = t.listPages(ctx, found.Interface(), &Options{Filter: filter}, func(page []byte, records interface{}) error {
+		pageRecords := reflect.ValueOf(records).Elem()
+		ids, err := linkedIDs(page, linkField, pageRecords)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < pageRecords.Len(); i++ {
+			if containsString(ids[i], recordID) {
+				list.Set(reflect.Append(list, pageRecords.Index(i)))
+			}
+		}
+		found.Elem().SetLen(0)
+		return nil
+	})
+	if err != nil {
+		list.Set(list.Slice(0, original))
+	}
+	return err
+}
+
+// linkedFromFormula builds the formula LinkedFrom lists records with.
+// It only uses the schema if it can be read.
+func (t *Table) linkedFromFormula(ctx context.Context, linkField, recordID string) (string, error) {
+	filter := formulaField(linkField)
+	schema, err := t.client.cachedSchema(ctx)
+	if err != nil {
+		return filter, nil
+	}
+	table := schema.Table(t.name)
+	if table == nil {
+		return filter, nil
+	}
+	field := table.Field(linkField)
+	if field == nil || field.Options == nil || field.Options.LinkedTableID == "" {
+		return filter, nil
+	}
+	linked := schema.Table(field.Options.LinkedTableID)
+	if linked == nil {
+		return filter, nil
+	}
+	primary := linked.Field(linked.PrimaryFieldID)
+	if primary == nil {
+		return filter, nil
+	}
+
+	linkedTable := t.client.Table(linked.Name)
+	res, err := t.client.RequestContext(ctx, "GET", linkedTable.makePath(recordID), nil)
+	if err != nil {
+		return "", err
+	}
+	record := struct {
+		Fields map[string]interface{}
+	}{}
+	if err := json.Unmarshal(res, &record); err != nil {
+		return "", err
+	}
+	value, ok := record.Fields[primary.Name].(string)
+	if !ok || value == "" {
+		return filter, nil
+	}
+	return fmt.Sprintf("AND(%s, FIND(%s, ARRAYJOIN(%s)))", filter, formulaString(value), filter), nil
+}
+
+// linkedIDs returns the IDs in linkField of each of records, a page of
+// records decoded from page. They're read from the records if their
+// Fields struct has the field, and from page otherwise.
+func linkedIDs(page []byte, linkField string, records reflect.Value) ([][]string, error) {
+	ids := make([][]string, records.Len())
+	fields, _ := records.Type().Elem().FieldByName("Fields")
+	if fields.Type.Kind() == reflect.Struct {
+		for i := 0; i < fields.Type.NumField(); i++ {
+			f := fields.Type.Field(i)
+			name, ok := fieldName(f)
+			if f.PkgPath != "" || !ok || name != linkField {
+				continue
+			}
+			if f.Type.Kind() != reflect.Slice || f.Type.Elem().Kind() != reflect.String {
+				break
+			}
+			for j := range ids {
+				cell := records.Index(j).FieldByName("Fields").Field(i)
+				for k := 0; k < cell.Len(); k++ {
+					ids[j] = append(ids[j], cell.Index(k).String())
+				}
+			}
+			return ids, nil
+		}
+	}
+
+	if page == nil {
+		return nil, fmt.Errorf("airtable: can't read %s from a page that was spilled to disk", linkField)
+	}
+	links := struct {
+		Records []struct {
+			Fields map[string]json.RawMessage
+		}
+	}{}
+	if err := json.Unmarshal(page, &links); err != nil {
+		return nil, err
+	}
+	for i, record := range links.Records {
+		if i == len(ids) {
+			break
+		}
+		if cell, ok := record.Fields[linkField]; ok {
+			if err := json.Unmarshal(cell, &ids[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ids, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,273 @@
+package airtable_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+type linkedBookRecord struct {
+	airtable.Record
+	Fields struct {
+		Title   string
+		Authors airtable.RecordLink
+	}
+}
+
+func TestBindLink(t *testing.T) {
+	var (
+		lookups int
+		created []string
+		sent    []string
+	)
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v0/appTEST/Authors":
+			lookups++
+			want := `OR({Name} = "Octavia Butler", {Name} = "N. K. \"Jemisin\"")`
+			if got := r.URL.Query().Get("filterByFormula"); got != want {
+				t.Errorf("unexpected formula %s", got)
+			}
+			fmt.Fprint(w, `{"records": [{"id": "recAAAAAAAAAAAAAA", "fields": {"Name": "Octavia Butler"}}]}`)
+		case r.Method == "POST" && r.URL.Path == "/v0/appTEST/Authors":
+			var body struct {
+				Fields   map[string]string
+				Typecast bool
+			}
+			b, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(b, &body)
+			if !body.Typecast {
+				t.Errorf("expected typecast to be sent, got %s", b)
+			}
+			created = append(created, body.Fields["Name"])
+			fmt.Fprint(w, `{"id": "recBBBBBBBBBBBBBB", "fields": {}}`)
+		case r.Method == "POST" && r.URL.Path == "/v0/appTEST/Books":
+			var body struct{ Fields struct{ Authors []string } }
+			b, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(b, &body)
+			sent = body.Fields.Authors
+			fmt.Fprintf(w, `{"id": "recBOOKBOOKBOOKBO", "fields": %s}`, b)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+	defer done()
+
+	books := client.Table("Books")
+	authors := client.Table("Authors")
+	authors.SetTypecast(true)
+	books.BindLink("Authors", airtable.LinkBinding{
+		Table:         authors,
+		KeyField:      "Name",
+		CreateMissing: true,
+	})
+
+	book := linkedBookRecord{}
+	book.Fields.Title = "Anthology"
+	book.Fields.Authors = airtable.RecordLink{
+		"Octavia Butler",
+		`N. K. "Jemisin"`,
+		"recCCCCCCCCCCCCCC",
+	}
+	if err := books.Create(&book); err != nil {
+		t.Fatal(err)
+	}
+
+	if lookups != 1 {
+		t.Errorf("expected 1 lookup, got %d", lookups)
+	}
+	if len(created) != 1 || created[0] != `N. K. "Jemisin"` {
+		t.Errorf("expected missing author to be created, got %v", created)
+	}
+	want := []string{"recAAAAAAAAAAAAAA", "recBBBBBBBBBBBBBB", "recCCCCCCCCCCCCCC"}
+	if fmt.Sprint(sent) != fmt.Sprint(want) {
+		t.Errorf("expected %v to be sent, got %v", want, sent)
+	}
+	if fmt.Sprint(book.Fields.Authors) != fmt.Sprint(want) {
+		t.Errorf("expected record to hold %v, got %v", want, book.Fields.Authors)
+	}
+}
+
+func TestBindLinkNotFound(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		fmt.Fprint(w, `{"records": []}`)
+	})
+	defer done()
+
+	books := client.Table("Books")
+	books.BindLink("Authors", airtable.LinkBinding{
+		Table:    client.Table("Authors"),
+		KeyField: "Name",
+	})
+
+	book := linkedBookRecord{}
+	book.Fields.Authors = airtable.RecordLink{"Nobody"}
+	err := books.Create(&book)
+	if _, ok := err.(airtable.ErrLinkNotFound); !ok {
+		t.Fatalf("expected ErrLinkNotFound, got %v", err)
+	}
+}
+
+func TestBindLinkContext(t *testing.T) {
+	var requests int
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"records": []}`)
+	})
+	defer done()
+
+	books := client.Table("Books")
+	books.BindLink("Authors", airtable.LinkBinding{
+		Table:         client.Table("Authors"),
+		KeyField:      "Name",
+		CreateMissing: true,
+	})
+
+	// the lookup of the natural key is bound to the context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	book := linkedBookRecord{}
+	book.Fields.Authors = airtable.RecordLink{"Octavia Butler"}
+	if err := books.CreateContext(ctx, &book); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the context's error, got %v", err)
+	}
+	if err := books.UpdateContext(ctx, &book); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the context's error, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected no requests, got %d", requests)
+	}
+}
+
+func TestLinkedFrom(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v0/meta/bases/appTEST/tables":
+			fmt.Fprint(w, `{"tables": [
+				{"id": "tblBOOKS", "name": "Books", "primaryFieldId": "fldTITLE", "fields": [
+					{"id": "fldTITLE", "name": "Title", "type": "singleLineText"},
+					{"id": "fldAUTHORS", "name": "Authors", "type": "multipleRecordLinks",
+						"options": {"linkedTableId": "tblAUTHORS"}}
+				]},
+				{"id": "tblAUTHORS", "name": "Authors", "primaryFieldId": "fldNAME", "fields": [
+					{"id": "fldNAME", "name": "Name", "type": "singleLineText"}
+				]}
+			]}`)
+			return
+		case "/v0/appTEST/Authors/recAUTHOR":
+			fmt.Fprint(w, `{"id": "recAUTHOR", "fields": {"Name": "Octavia Butler"}}`)
+			return
+		case "/v0/appTEST/Books":
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		want := `AND({Authors}, FIND("Octavia Butler", ARRAYJOIN({Authors})))`
+		if got := r.URL.Query().Get("filterByFormula"); got != want {
+			t.Errorf("unexpected formula %s", got)
+		}
+		if r.URL.Query().Get("offset") == "" {
+			fmt.Fprint(w, `{"offset": "next", "records": [
+				{"id": "rec1", "fields": {"Title": "one", "Authors": ["recAUTHOR", "recOTHER"]}},
+				{"id": "rec2", "fields": {"Title": "two", "Authors": ["recOTHER"]}}
+			]}`)
+			return
+		}
+		fmt.Fprint(w, `{"records": [
+			{"id": "rec3", "fields": {"Title": "three", "Authors": ["recAUTHOR"]}}
+		]}`)
+	})
+	defer done()
+
+	table := client.Table("Books")
+	books := []linkedBookRecord{}
+	if err := table.LinkedFrom("Authors", "recAUTHOR", &books); err != nil {
+		t.Fatal(err)
+	}
+	if len(books) != 2 || books[0].ID != "rec1" || books[1].Fields.Title != "three" {
+		t.Errorf("unexpected books %+v", books)
+	}
+}
+
+func TestRecordLinkHydrate(t *testing.T) {
+	requests := 0
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		want := `OR(RECORD_ID() = "recBBBBBBBBBBBBBB", RECORD_ID() = "recAAAAAAAAAAAAAA")`
+		if got := r.URL.Query().Get("filterByFormula"); got != want {
+			t.Errorf("unexpected formula %s", got)
+		}
+		fmt.Fprint(w, `{"records": [
+			{"id": "recAAAAAAAAAAAAAA", "fields": {"Title": "a"}},
+			{"id": "recBBBBBBBBBBBBBB", "fields": {"Title": "b"}}
+		]}`)
+	})
+	defer done()
+
+	link := airtable.RecordLink{"recBBBBBBBBBBBBBB", "Unresolved key", "recAAAAAAAAAAAAAA", "recBBBBBBBBBBBBBB"}
+	table := client.Table("Tasks")
+	var records []taskRecord
+	if err := link.Hydrate(table, &records); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 || len(records) != 2 || records[0].Fields.Title != "b" || records[1].Fields.Title != "a" {
+		t.Errorf("unexpected records %+v after %d requests", records, requests)
+	}
+}
+
+func TestLinkedFromBadCell(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"records": [
+			{"id": "rec1", "fields": {"Title": "one", "Authors": "recAUTHOR"}}
+		]}`)
+	})
+	defer done()
+
+	table := client.Table("Books")
+	books := []linkedBookRecord{}
+	err := table.LinkedFrom("Authors", "recAUTHOR", &books)
+	if _, ok := err.(*json.UnmarshalTypeError); !ok {
+		t.Fatalf("expected an UnmarshalTypeError, got %v", err)
+	}
+	if len(books) != 0 {
+		t.Errorf("expected no books, got %+v", books)
+	}
+}
+
+func TestLinkedFromContextWithoutLinkField(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/meta/") {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error": {"type": "INVALID_PERMISSIONS"}}`)
+			return
+		}
+		if got := r.URL.Query().Get("filterByFormula"); got != "{Authors}" {
+			t.Errorf("expected only the link field to be filtered on, got %s", got)
+		}
+		fmt.Fprint(w, `{"records": [
+			{"id": "rec1", "fields": {"Title": "one", "Authors": ["recOTHER"]}},
+			{"id": "rec2", "fields": {"Title": "two", "Authors": ["recAUTHOR"]}}
+		]}`)
+	})
+	defer done()
+
+	// the record struct doesn't have the link field, so the links are
+	// read from the page.
+	table := client.Table("Books")
+	books := []taskRecord{}
+	if err := table.LinkedFromContext(context.Background(), "Authors", "recAUTHOR", &books); err != nil {
+		t.Fatal(err)
+	}
+	if len(books) != 1 || books[0].ID != "rec2" || books[0].Fields.Title != "two" {
+		t.Errorf("unexpected books %+v", books)
+	}
+}
@@ -0,0 +1,320 @@
+package airtable_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestListRetriesPage(t *testing.T) {
+	failures := 2
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "" {
+			fmt.Fprint(w, recordsResponse(0, 3, "page2"))
+			return
+		}
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"error": "SERVICE_UNAVAILABLE"}`)
+			return
+		}
+		fmt.Fprint(w, recordsResponse(3, 2, ""))
+	})
+	defer done()
+	client.PageRetries = 2
+	client.PageRetryBackoff = time.Millisecond
+	var retries []int
+	client.OnRetry = func(req airtable.RequestInfo, err error, attempt int) {
+		if req.Method != "GET" || req.RequestID == "" || err == nil {
+			t.Errorf("unexpected retry of %+v: %v", req, err)
+		}
+		retries = append(retries, attempt)
+	}
+
+	table := client.Table("Books")
+	records := []taskRecord{}
+	if err := table.List(&records, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 5 {
+		t.Errorf("expected 5 records, got %d", len(records))
+	}
+	if fmt.Sprint(retries) != "[1 2]" {
+		t.Errorf("expected OnRetry to be called for each retry, got %v", retries)
+	}
+}
+
+func TestListResume(t *testing.T) {
+	fail := true
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "" {
+			fmt.Fprint(w, recordsResponse(0, 3, "page2"))
+			return
+		}
+		if fail {
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"errors": [{"error": "RATE_LIMIT_REACHED"}], "error": "RATE_LIMIT_REACHED"}`)
+			return
+		}
+		fmt.Fprint(w, recordsResponse(3, 2, ""))
+	})
+	defer done()
+	client.PageRetries = -1
+
+	table := client.Table("Books")
+	records := []taskRecord{}
+	if err := table.List(&records, nil); err == nil || len(records) != 0 {
+		t.Fatalf("expected list to be untouched on error, got %d records, %v", len(records), err)
+	}
+
+	options := &airtable.Options{Partial: true}
+	err := table.List(&records, options)
+	interrupted, ok := err.(airtable.ErrListInterrupted)
+	if !ok {
+		t.Fatalf("expected ErrListInterrupted, got %v", err)
+	}
+	if interrupted.Offset != "page2" || options.Offset != "page2" {
+		t.Errorf("expected offset page2, got %q and %q", interrupted.Offset, options.Offset)
+	}
+	if len(records) != 3 {
+		t.Errorf("expected the first page to be kept, got %d records", len(records))
+	}
+
+	fail = false
+	if err := table.List(&records, options); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 5 {
+		t.Errorf("expected 5 records after resuming, got %d", len(records))
+	}
+}
+
+func TestListPagesDontShareValues(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "" {
+			fmt.Fprint(w, `{"offset": "page2", "records": [{"id": "rec1", "fields": {"Title": "a", "Done": true}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"records": [{"id": "rec2", "fields": {"Title": "b"}}]}`)
+	})
+	defer done()
+
+	// without an embedded Record, pages are decoded in one go.
+	type plainTask struct {
+		ID     string
+		Fields struct {
+			Title string
+			Done  bool
+		}
+	}
+	table := client.Table("Tasks")
+	records := []plainTask{}
+	if err := table.List(&records, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	if !records[0].Fields.Done || records[1].Fields.Done {
+		t.Errorf("expected only the first record to be done, got %+v", records)
+	}
+}
+
+func TestListPrefetch(t *testing.T) {
+	fail := true
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("offset") {
+		case "":
+			fmt.Fprint(w, recordsResponse(0, 3, "page2"))
+		case "page2":
+			fmt.Fprint(w, recordsResponse(3, 3, "page3"))
+		default:
+			if fail {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				fmt.Fprint(w, `{"error": "INVALID_REQUEST"}`)
+				return
+			}
+			fmt.Fprint(w, recordsResponse(6, 1, ""))
+		}
+	})
+	defer done()
+
+	table := client.Table("Books")
+	records := []taskRecord{}
+	options := &airtable.Options{Prefetch: true, Partial: true}
+	err := table.List(&records, options)
+	// an invalid request isn't temporary, so it's returned as it is.
+	if _, ok := err.(airtable.ErrClientRequest); !ok || options.Offset != "page3" {
+		t.Fatalf("expected a request error at page3, got %v at %q", err, options.Offset)
+	}
+	if len(records) != 6 {
+		t.Errorf("expected the first two pages to be kept, got %d records", len(records))
+	}
+
+	fail = false
+	if err := table.List(&records, options); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 7 || records[6].ID != "rec6" {
+		t.Errorf("unexpected records %+v", records)
+	}
+}
+
+func TestListPage(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "" {
+			fmt.Fprint(w, recordsResponse(0, 3, "page2"))
+			return
+		}
+		fmt.Fprint(w, recordsResponse(3, 2, ""))
+	})
+	defer done()
+
+	table := client.Table("Books")
+	records := []taskRecord{}
+	options := &airtable.Options{}
+	offset, err := table.ListPage(&records, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offset != "page2" || len(records) != 3 || options.Offset != "" {
+		t.Errorf("unexpected first page: offset %q, %d records, options %+v", offset, len(records), options)
+	}
+
+	options.Offset = offset
+	if offset, err = table.ListPage(&records, options); err != nil {
+		t.Fatal(err)
+	}
+	if offset != "" || len(records) != 5 {
+		t.Errorf("unexpected last page: offset %q, %d records", offset, len(records))
+	}
+}
+
+func TestListPages(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("offset") {
+		case "":
+			fmt.Fprint(w, recordsResponse(0, 3, "page2"))
+		case "page2":
+			fmt.Fprint(w, recordsResponse(3, 2, "page3"))
+		default:
+			t.Error("expected listing to stop after the second page")
+		}
+	})
+	defer done()
+
+	table := client.Table("Books")
+	records := []taskRecord{}
+	options := &airtable.Options{Partial: true}
+	stop := errors.New("stop")
+	var offsets []string
+	err := table.ListPages(&records, options, func(page []byte, pageRecords interface{}) error {
+		offsets = append(offsets, options.Offset)
+		if len(page) == 0 {
+			t.Error("expected the page's body")
+		}
+		if n := len(*pageRecords.(*[]taskRecord)); n != 3 && n != 2 {
+			t.Errorf("unexpected page of %d records", n)
+		}
+		if options.Offset == "page3" {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("expected the callback's error, got %v", err)
+	}
+	if fmt.Sprint(offsets) != "[page2 page3]" || len(records) != 5 {
+		t.Errorf("unexpected offsets %v and %d records", offsets, len(records))
+	}
+}
+
+func TestListStreamCutOff(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "" {
+			fmt.Fprint(w, recordsResponse(0, 3, "page2"))
+			return
+		}
+		page := recordsResponse(3, 3, "")
+		w.Header().Set("Content-Length", fmt.Sprint(len(page)))
+		fmt.Fprint(w, page[:len(page)/2])
+	})
+	defer done()
+
+	table := client.Table("Books")
+	records := []taskRecord{}
+	options := &airtable.Options{Partial: true}
+	err := table.List(&records, options)
+	if _, ok := err.(airtable.ErrListInterrupted); !ok || options.Offset != "page2" {
+		t.Fatalf("expected list to be interrupted at page2, got %v at %q", err, options.Offset)
+	}
+	if len(records) != 3 {
+		t.Errorf("expected only the first page to be kept, got %d records", len(records))
+	}
+}
+
+func TestListStreamCutOffRetries(t *testing.T) {
+	var cutOff int
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "" {
+			fmt.Fprint(w, recordsResponse(0, 3, "page2"))
+			return
+		}
+		page := recordsResponse(3, 3, "")
+		if cutOff < 2 {
+			cutOff++
+			w.Header().Set("Content-Length", fmt.Sprint(len(page)))
+			fmt.Fprint(w, page[:len(page)/2])
+			return
+		}
+		fmt.Fprint(w, page)
+	})
+	defer done()
+	client.PageRetries = 2
+	client.PageRetryBackoff = time.Millisecond
+	var retries []int
+	client.OnRetry = func(req airtable.RequestInfo, err error, attempt int) {
+		if !strings.Contains(req.URL, "offset=page2") {
+			t.Errorf("expected page2 to be retried, got %s", req.URL)
+		}
+		retries = append(retries, attempt)
+	}
+
+	table := client.Table("Books")
+	records := []taskRecord{}
+	if err := table.List(&records, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 6 || records[5].ID != "rec5" {
+		t.Errorf("expected both pages once, got %+v", records)
+	}
+	if fmt.Sprint(retries) != "[1 2]" {
+		t.Errorf("expected two retries, got %v", retries)
+	}
+}
+
+func TestListIntoMap(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "" {
+			fmt.Fprint(w, `{"offset": "page2", "records": [{"id": "rec1", "fields": {"Title": "a"}}, {"id": "rec2", "fields": {"Title": "b"}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"records": [{"id": "rec3", "fields": {"Title": "c", "Done": true}}]}`)
+	})
+	defer done()
+
+	table := client.Table("Tasks")
+	var tasks map[string]taskRecord
+	if err := table.ListIntoMap(&tasks, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 3 || tasks["rec1"].Fields.Title != "a" || tasks["rec2"].Fields.Title != "b" || !tasks["rec3"].Fields.Done {
+		t.Errorf("unexpected records %+v", tasks)
+	}
+}
@@ -0,0 +1,74 @@
+package airtable
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// ListIntoMap is like List but stores the records in the map pointed to
+// by mapPtr, keyed by record ID, instead of appending them to a slice.
+// The map is created if it's nil. mapPtr must be a pointer to a map from
+// string to a record type like the element type of listPtr in List:
+//
+//	books := map[string]BookRecord{}
+//	err := table.ListIntoMap(&books, nil)
+//
+// As with List, options.Offset is kept up to date so an interrupted
+// listing can be resumed. Records from the pages before an error are
+// left in the map.
+func (t *Table) ListIntoMap(mapPtr interface{}, options *Options) error {
+	return t.ListIntoMapContext(context.Background(), mapPtr, options)
+}
+
+// ListIntoMapContext is like ListIntoMap but the requests are bound to
+// ctx.
+func (t *Table) ListIntoMapContext(ctx context.Context, mapPtr interface{}, options *Options) error {
+	typ := reflect.TypeOf(mapPtr)
+	if typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Map || typ.Elem().Key().Kind() != reflect.String {
+		return t.argError(ErrInvalidArg{Arg: "mapPtr", Reason: fmt.Sprintf("must be a pointer to a map with string keys, got %s", typ)})
+	}
+	m := reflect.ValueOf(mapPtr).Elem()
+	recordType := m.Type().Elem()
+
+	// each page is decoded into the same slice and copied into the map.
+	page := reflect.New(reflect.SliceOf(recordType))
+	if err := t.argError(checkListArg(page.Interface())); err != nil {
+		return err
+	}
+
+	if options == nil {
+		options = &Options{}
+	}
+	options.setType(recordType)
+	if err := t.argError(options.check()); err != nil {
+		return err
+	}
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+
+	decoder := t.client.newListDecoder(page.Interface())
+	for {
+		body, err := t.client.requestPage(withStreamedBody(ctx), t.makePath(""), options)
+		if err != nil {
+			body.close()
+			return listError(options.Offset, err)
+		}
+		page.Elem().SetLen(0)
+		offset, err := decoder.read(body)
+		body.close()
+		if err != nil {
+			return err
+		}
+		records := page.Elem()
+		for i := 0; i < records.Len(); i++ {
+			record := records.Index(i)
+			m.SetMapIndex(record.FieldByName("ID"), record)
+		}
+		options.Offset = offset
+		if options.Offset == "" {
+			return nil
+		}
+	}
+}
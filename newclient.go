@@ -0,0 +1,140 @@
+package airtable
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.uber.org/ratelimit"
+)
+
+// Option configures a Client made by NewClient. Options return an error
+// for settings that are invalid, which NewClient passes on.
+type Option func(c *Client) error
+
+// NewClient makes a client for the base with the given ID, applying the
+// options in order and filling in the defaults for the rest, as they're
+// filled in on first use for clients made as struct literals. Unlike
+// those clients, which panic on their first request if they're missing
+// their API key or base ID, NewClient returns an error.
+func NewClient(apiKey, baseID string, opts ...Option) (*Client, error) {
+	if apiKey == "" {
+		return nil, errors.New("airtable: missing API key")
+	}
+	if baseID == "" {
+		return nil, errors.New("airtable: missing base ID")
+	}
+	c := &Client{APIKey: apiKey, BaseID: baseID}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	c.checkSetup()
+	return c, nil
+}
+
+// WithHTTPClient sets the client's HTTPClient.
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *Client) error {
+		if h == nil {
+			return errors.New("airtable: nil HTTP client")
+		}
+		c.HTTPClient = h
+		return nil
+	}
+}
+
+// WithTransport sets the client's Transport.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) error {
+		c.Transport = rt
+		return nil
+	}
+}
+
+// WithRateLimit sets the client's Limiter to one allowing n requests per
+// second, or any number of requests if n is 0.
+func WithRateLimit(n int) Option {
+	return func(c *Client) error {
+		if n < 0 {
+			return fmt.Errorf("airtable: invalid rate limit %d", n)
+		}
+		c.Limiter = RateLimiter(n)
+		return nil
+	}
+}
+
+// WithLimiter sets the client's Limiter.
+func WithLimiter(l ratelimit.Limiter) Option {
+	return func(c *Client) error {
+		c.Limiter = l
+		return nil
+	}
+}
+
+// WithRootURL sets the client's RootURL, which must be an absolute URL.
+func WithRootURL(rootURL string) Option {
+	return func(c *Client) error {
+		if err := checkRootURL(rootURL); err != nil {
+			return err
+		}
+		c.RootURL = rootURL
+		return nil
+	}
+}
+
+// WithContentURL sets the client's ContentURL, which must be an
+// absolute URL.
+func WithContentURL(contentURL string) Option {
+	return func(c *Client) error {
+		if err := checkRootURL(contentURL); err != nil {
+			return err
+		}
+		c.ContentURL = contentURL
+		return nil
+	}
+}
+
+// WithEndpoint sets the client's Endpoint.
+func WithEndpoint(endpoint Endpoint) Option {
+	return func(c *Client) error {
+		c.Endpoint = endpoint
+		return nil
+	}
+}
+
+// WithVersion sets the version of the API the client uses.
+func WithVersion(version string) Option {
+	return func(c *Client) error {
+		c.Version = version
+		return nil
+	}
+}
+
+// WithPageRetries sets how many times List retries a page and how long
+// it waits before the first retry. See Client.PageRetries.
+func WithPageRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) error {
+		if backoff < 0 {
+			return fmt.Errorf("airtable: invalid page retry backoff %s", backoff)
+		}
+		c.PageRetries = retries
+		c.PageRetryBackoff = backoff
+		return nil
+	}
+}
+
+// checkRootURL returns an error if u isn't an absolute URL.
+func checkRootURL(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return fmt.Errorf("airtable: invalid URL %q: %s", u, err)
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return fmt.Errorf("airtable: invalid URL %q: not absolute", u)
+	}
+	return nil
+}
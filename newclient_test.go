@@ -0,0 +1,57 @@
+package airtable_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestNewClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer keyTEST" {
+			t.Errorf("unexpected authorization %s", r.Header.Get("Authorization"))
+		}
+		fmt.Fprint(w, `{"id": "rec1", "fields": {"Title": "a"}}`)
+	}))
+	defer server.Close()
+
+	client, err := airtable.NewClient("keyTEST", "appTEST",
+		airtable.WithRootURL(server.URL),
+		airtable.WithRateLimit(0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.RootURL != server.URL || client.Version != airtable.DefaultVersion {
+		t.Errorf("unexpected client %+v", client)
+	}
+	record := taskRecord{}
+	table := client.Table("Tasks")
+	if err := table.Get("rec1", &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Fields.Title != "a" {
+		t.Errorf("unexpected record %+v", record)
+	}
+}
+
+func TestNewClientErrors(t *testing.T) {
+	tests := []struct {
+		apiKey, baseID string
+		opts           []airtable.Option
+	}{
+		{"", "appTEST", nil},
+		{"keyTEST", "", nil},
+		{"keyTEST", "appTEST", []airtable.Option{airtable.WithRootURL("api.example.com")}},
+		{"keyTEST", "appTEST", []airtable.Option{airtable.WithRateLimit(-1)}},
+		{"keyTEST", "appTEST", []airtable.Option{airtable.WithHTTPClient(nil)}},
+	}
+	for i, test := range tests {
+		if client, err := airtable.NewClient(test.apiKey, test.baseID, test.opts...); err == nil {
+			t.Errorf("%d: expected an error, got client %+v", i, client)
+		}
+	}
+}
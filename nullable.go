@@ -0,0 +1,71 @@
+//go:build go1.18
+
+package airtable
+
+import "encoding/json"
+
+// Nullable is a field that tells apart a cell that's absent, one that's
+// null and one that holds a value, even the zero value of T. Airtable
+// leaves empty cells out of responses, so a Nullable[int] that's absent
+// after decoding is an empty cell, not a 0.
+//
+// On writes, an absent Nullable is left out of the request, so the cell
+// is left alone, a null one clears the cell and one with a value sets
+// it. The zero Nullable is absent.
+type Nullable[T any] struct {
+	value   T
+	valid   bool
+	present bool
+}
+
+// Some returns a Nullable holding v.
+func Some[T any](v T) Nullable[T] {
+	return Nullable[T]{value: v, valid: true, present: true}
+}
+
+// Null returns a Nullable that's null.
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{present: true}
+}
+
+// Get returns the value, or false if n is absent or null.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.value, n.valid
+}
+
+// IsNull reports whether n is null.
+func (n Nullable[T]) IsNull() bool {
+	return n.present && !n.valid
+}
+
+// IsPresent reports whether n is null or holds a value.
+func (n Nullable[T]) IsPresent() bool {
+	return n.present
+}
+
+func (n Nullable[T]) absent() bool {
+	return !n.present
+}
+
+// UnmarshalJSON decodes null or a value. It's only called for cells
+// that are in the response, so n stays absent otherwise.
+func (n *Nullable[T]) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*n = Null[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*n = Some(v)
+	return nil
+}
+
+// MarshalJSON encodes the value, or null if n is null or absent.
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.value)
+}
@@ -0,0 +1,61 @@
+//go:build go1.18
+
+package airtable_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+type counter struct {
+	airtable.Record
+	Fields struct {
+		Count  airtable.Nullable[int]
+		Done   airtable.Nullable[bool]
+		Misses airtable.Nullable[int]
+	}
+}
+
+func TestNullableDecode(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "rec1", "fields": {"Count": 0, "Done": null}}`)
+	})
+	defer done()
+
+	record := counter{}
+	table := client.Table("Counters")
+	if err := table.Get("rec1", &record); err != nil {
+		t.Fatal(err)
+	}
+	if n, ok := record.Fields.Count.Get(); !ok || n != 0 {
+		t.Errorf("expected a count of 0, got %v %v", n, ok)
+	}
+	if !record.Fields.Done.IsNull() {
+		t.Errorf("expected done to be null, got %+v", record.Fields.Done)
+	}
+	if record.Fields.Misses.IsPresent() {
+		t.Errorf("expected misses to be absent, got %+v", record.Fields.Misses)
+	}
+}
+
+func TestNullableEncode(t *testing.T) {
+	handler, bodies := recordBodies()
+	client, done := newTestClient(handler)
+	defer done()
+
+	record := counter{}
+	record.ID = "rec1"
+	record.Fields.Count = airtable.Some(0)
+	record.Fields.Done = airtable.Null[bool]()
+	table := client.Table("Counters")
+	if err := table.Update(&record); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"fields": {"Count":0,"Done":null}, "typecast": false}`
+	if (*bodies)[0] != want {
+		t.Errorf("unexpected body\n got: %s\nwant: %s", (*bodies)[0], want)
+	}
+}
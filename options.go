@@ -7,21 +7,45 @@ import (
 	"strings"
 )
 
+// SortDirection indicates which direction to sort the results in.
+type SortDirection string
+
 // SortType indicates which direction to sort the results in.
-type SortType string
+//
+// Deprecated: use SortDirection.
+type SortType = SortDirection
 
-// SortDesc and SortAsc are used in the Sort type to indicate the
-// direction of the sort.
+// SortDesc and SortAsc are the directions to sort in, for a SortField
+// or a Sort. An empty direction sorts ascending.
 const (
 	SortDesc = "desc"
 	SortAsc  = "asc"
 )
 
+// SortField is a field to sort the results by, and the direction to sort
+// it in. Field can be the name of a field of the record's Fields struct,
+// the Airtable name of the field or its ID.
+type SortField struct {
+	Field     string
+	Direction SortDirection
+}
+
+// Sort represents a pair of strings: a field and a SortType
+//
+// Deprecated: use Options.SortBy.
+type Sort [][2]string
+
 // Options is used in the Table.List method to adjust and control the response
 type Options struct {
 	// Sort the response. See the package example for usage usage
+	//
+	// Deprecated: use SortBy.
 	Sort Sort
 
+	// Sort the response by these fields, in order, after the ones in
+	// Sort.
+	SortBy []SortField
+
 	// Which fields to include. Useful when you want to exclude certain
 	// fields if you aren't using them to save on network cost.
 	Fields []string
@@ -46,12 +70,51 @@ type Options struct {
 	// helpful for integrating with 3rd party data sources.
 	Typecast bool
 
-	offset string
-	typ    reflect.Type
-}
+	// Offset to start listing from. It's empty to start at the first
+	// page; List sets it as it goes from page to page, so if List is
+	// interrupted it holds the offset of the page that failed (see
+	// ErrListInterrupted) and calling List again with the same Options
+	// resumes from there.
+	Offset string
 
-// Sort represents a pair of strings: a field and a SortType
-type Sort [][2]string
+	// Keep the records from the pages that were fetched before List
+	// failed. By default, the list is left the way it was before List
+	// was called when there's an error. Set this when resuming with
+	// Offset so the records from earlier attempts aren't lost.
+	Partial bool
+
+	// Request the next page while the current one is being decoded, so
+	// the network and decoding overlap. Requests still wait on the
+	// client's limiter. Pages are read into memory to find the next
+	// offset, instead of being decoded as they arrive as they otherwise
+	// are, and pages that were spilled to disk (see
+	// Client.MaxResponseSize) aren't prefetched from.
+	Prefetch bool
+
+	// Format of cell values: "json", the default, or "string" to get
+	// every value as it's displayed in Airtable. The API requires
+	// TimeZone and UserLocale with "string".
+	CellFormat string
+
+	// Time zone to render dates in with CellFormat "string", e.g.
+	// "Europe/Berlin".
+	TimeZone string
+
+	// Locale to render dates and numbers in with CellFormat "string",
+	// e.g. "de".
+	UserLocale string
+
+	// Key the fields in the response by field ID instead of by name.
+	// The record's Fields struct then needs JSON tags with the IDs.
+	ReturnFieldsByFieldID bool
+
+	// Extra query parameters to send, for parameters the API supports
+	// that Options doesn't have a field for yet. They're sent after,
+	// and in addition to, the ones from the other fields.
+	Extra url.Values
+
+	typ reflect.Type
+}
 
 func (o *Options) setType(t reflect.Type) {
 	o.typ = t
@@ -61,8 +124,8 @@ func (o *Options) setType(t reflect.Type) {
 func (o Options) Encode() string {
 	q := []string{}
 
-	if o.offset != "" {
-		q = append(q, "offset="+esc(o.offset))
+	if o.Offset != "" {
+		q = append(q, "offset="+esc(o.Offset))
 	}
 
 	if o.Typecast != false {
@@ -77,23 +140,46 @@ func (o Options) Encode() string {
 		q = append(q, "view="+esc(o.View))
 	}
 
+	if o.CellFormat != "" {
+		q = append(q, "cellFormat="+esc(o.CellFormat))
+	}
+
+	if o.TimeZone != "" {
+		q = append(q, "timeZone="+esc(o.TimeZone))
+	}
+
+	if o.UserLocale != "" {
+		q = append(q, "userLocale="+esc(o.UserLocale))
+	}
+
+	if o.ReturnFieldsByFieldID {
+		q = append(q, "returnFieldsByFieldId=true")
+	}
+
 	if o.MaxRecords != 0 {
 		q = append(q, fmt.Sprintf("maxRecords=%d", o.MaxRecords))
 	}
 
 	// This creates encoded version of something like this:
 	// "sort[0][field]=Name&sort[0][direction]=desc". It will look up
-	// the JSON tag on the related field in the struct passed in to
-	// hold the response. If there's no JSON tag, it uses the raw
-	// field name.
-	if len(o.Sort) != 0 {
-		for i, sort := range o.Sort {
-			field, direction := getFieldJSONName(sort[0], o.typ), sort[1]
+	// the airtable or JSON tag on the related field in the struct
+	// passed in to hold the response. If there's neither, it uses the
+	// raw field name. Encode panics if a direction isn't SortAsc or
+	// SortDesc.
+	if sorts := o.sortFields(); len(sorts) != 0 {
+		for i, sort := range sorts {
+			field, direction := getFieldJSONName(sort.Field, o.typ), sort.Direction
+			if err := checkSortDirection(sort); err != nil {
+				panic(err)
+			}
+			if direction == "" {
+				direction = SortAsc
+			}
 			sortstr := fmt.Sprintf("%s=%s&%s=%s",
 				esc(fmt.Sprintf("sort[%d][field]", i)),
 				esc(field),
 				esc(fmt.Sprintf("sort[%d][direction]", i)),
-				esc(direction),
+				esc(string(direction)),
 			)
 			q = append(q, sortstr)
 		}
@@ -110,20 +196,92 @@ func (o Options) Encode() string {
 		}
 	}
 
+	if len(o.Extra) != 0 {
+		q = append(q, o.Extra.Encode())
+	}
+
 	query := strings.Join(q, "&")
 	return query
 }
 
+// sortFields returns the fields to sort by, from Sort and SortBy.
+func (o Options) sortFields() []SortField {
+	if len(o.Sort) == 0 {
+		return o.SortBy
+	}
+	sorts := make([]SortField, 0, len(o.Sort)+len(o.SortBy))
+	for _, sort := range o.Sort {
+		sorts = append(sorts, SortField{sort[0], SortDirection(sort[1])})
+	}
+	return append(sorts, o.SortBy...)
+}
+
+// check returns an ErrInvalidArg if the options name a field that isn't
+// in the record type or sort in a direction other than SortAsc or
+// SortDesc, which Encode would panic on.
+func (o Options) check() error {
+	for _, sort := range o.sortFields() {
+		if err := checkSortDirection(sort); err != nil {
+			return err
+		}
+		if _, err := lookupFieldName(sort.Field, o.typ); err != nil {
+			return err
+		}
+	}
+	for _, name := range o.Fields {
+		if _, err := lookupFieldName(name, o.typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSortDirection returns an ErrInvalidArg if sort's direction isn't
+// SortAsc, SortDesc or empty.
+func checkSortDirection(sort SortField) error {
+	switch sort.Direction {
+	case "", SortAsc, SortDesc:
+		return nil
+	}
+	return ErrInvalidArg{Arg: "options", Reason: fmt.Sprintf("could not sort by %s: invalid direction %q", sort.Field, sort.Direction)}
+}
+
+// getFieldJSONName is like lookupFieldName but panics if there's no
+// such field.
 func getFieldJSONName(field string, t reflect.Type) string {
+	name, err := lookupFieldName(field, t)
+	if err != nil {
+		panic(err)
+	}
+	return name
+}
+
+// lookupFieldName looks up the name Airtable uses for the field in the
+// record type t. field can be the name of a field of the Fields struct
+// or already the Airtable name, as it is when it comes from the schema,
+// or the field's ID. When there's no record type, as with operations on
+// untyped records, or its Fields aren't a struct, field is taken to be
+// the Airtable name. It returns an ErrInvalidArg if t has no such field.
+func lookupFieldName(field string, t reflect.Type) (string, error) {
+	if t == nil || isFieldID(field) {
+		return field, nil
+	}
 	fields, _ := t.FieldByName("Fields")
-	f, ok := fields.Type.FieldByName(field)
-	if !ok {
-		panic(fmt.Errorf("could not sort by %s: no such field in %s", field, t))
+	if fields.Type.Kind() != reflect.Struct {
+		return field, nil
 	}
-	if json, ok := f.Tag.Lookup("json"); ok {
-		field = json
+	if f, ok := fields.Type.FieldByName(field); ok {
+		if name, ok := fieldName(f); ok {
+			field = name
+		}
+		return field, nil
+	}
+	for i := 0; i < fields.Type.NumField(); i++ {
+		if name, ok := fieldName(fields.Type.Field(i)); ok && name == field {
+			return field, nil
+		}
 	}
-	return field
+	return "", ErrInvalidArg{Arg: "options", Reason: fmt.Sprintf("names %s, no such field in %s", field, t)}
 }
 
 func esc(s string) string {
@@ -0,0 +1,158 @@
+package airtable_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestListSort(t *testing.T) {
+	var query string
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		fmt.Fprint(w, `{"records": []}`)
+	})
+	defer done()
+
+	type book struct {
+		airtable.Record
+		Fields struct {
+			Title  string `json:"Book Title"`
+			Rating float64
+		}
+	}
+	table := client.Table("Books")
+	records := []book{}
+	err := table.List(&records, &airtable.Options{
+		Sort: airtable.Sort{{"Title", airtable.SortAsc}},
+		SortBy: []airtable.SortField{
+			{Field: "Book Title", Direction: airtable.SortDesc},
+			{Field: "fldRATING00000000"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "sort%5B0%5D%5Bfield%5D=Book+Title&sort%5B0%5D%5Bdirection%5D=asc&" +
+		"sort%5B1%5D%5Bfield%5D=Book+Title&sort%5B1%5D%5Bdirection%5D=desc&" +
+		"sort%5B2%5D%5Bfield%5D=fldRATING00000000&sort%5B2%5D%5Bdirection%5D=asc"
+	if query != want {
+		t.Errorf("unexpected query\n got: %s\nwant: %s", query, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected an invalid direction to panic")
+		}
+	}()
+	options := airtable.Options{SortBy: []airtable.SortField{{Field: "Rating", Direction: "down"}}}
+	options.Encode()
+}
+
+func TestListFieldNameTagOptions(t *testing.T) {
+	var query url.Values
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprint(w, `{"records": []}`)
+	})
+	defer done()
+
+	// the json tag's options aren't part of the field's name.
+	type task struct {
+		airtable.Record
+		Fields struct {
+			Due   string `json:"Due Date,omitempty"`
+			Notes string `json:",omitempty"`
+		}
+	}
+	table := client.Table("Tasks")
+	records := []task{}
+	err := table.List(&records, &airtable.Options{
+		Fields: []string{"Due", "Notes"},
+		SortBy: []airtable.SortField{{Field: "Due"}, {Field: "Due Date"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := query.Get("fields[0]") + "," + query.Get("fields[1]"); got != "Due Date,Notes" {
+		t.Errorf("unexpected fields %q", got)
+	}
+	if got := query.Get("sort[0][field]") + "," + query.Get("sort[1][field]"); got != "Due Date,Due Date" {
+		t.Errorf("unexpected sort fields %q", got)
+	}
+}
+
+func TestSortCompat(t *testing.T) {
+	// the deprecated Sort takes plain strings.
+	dir := "desc"
+	var asc string = airtable.SortAsc
+	sort := airtable.Sort{{"Rating", dir}, {"Title", asc}}
+	pairs := [][2]string(sort)
+	options := airtable.Options{Sort: sort}
+	want := "sort%5B0%5D%5Bfield%5D=Rating&sort%5B0%5D%5Bdirection%5D=desc&" +
+		"sort%5B1%5D%5Bfield%5D=Title&sort%5B1%5D%5Bdirection%5D=asc"
+	if got := options.Encode(); got != want || len(pairs) != 2 {
+		t.Errorf("unexpected query\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestGetWithOptions(t *testing.T) {
+	var query string
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		fmt.Fprint(w, `{"id": "rec1", "fields": {"fldTITLE00000000": "a"}}`)
+	})
+	defer done()
+
+	type task struct {
+		airtable.Record
+		Fields struct {
+			Title string `json:"fldTITLE00000000"`
+		}
+	}
+	table := client.Table("Tasks")
+	record := task{}
+	err := table.GetWithOptions("rec1", &record, &airtable.Options{
+		Fields:                []string{"Title"},
+		Filter:                "{Done}",
+		CellFormat:            "string",
+		ReturnFieldsByFieldID: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the single-record endpoint has no fields filter.
+	want := "cellFormat=string&returnFieldsByFieldId=true"
+	if query != want {
+		t.Errorf("unexpected query\n got: %s\nwant: %s", query, want)
+	}
+	if record.Fields.Title != "a" {
+		t.Errorf("unexpected record %+v", record)
+	}
+}
+
+func TestEncodeCellFormat(t *testing.T) {
+	options := airtable.Options{
+		CellFormat: "string",
+		TimeZone:   "Europe/Berlin",
+		UserLocale: "de",
+	}
+	want := "cellFormat=string&timeZone=Europe%2FBerlin&userLocale=de"
+	if got := options.Encode(); got != want {
+		t.Errorf("unexpected query\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestEncodeExtra(t *testing.T) {
+	options := airtable.Options{
+		View:  "Grid view",
+		Extra: url.Values{"recordMetadata[]": {"commentCount"}, "pageSize": {"10"}},
+	}
+	want := "view=Grid+view&pageSize=10&recordMetadata%5B%5D=commentCount"
+	if got := options.Encode(); got != want {
+		t.Errorf("unexpected query\n got: %s\nwant: %s", got, want)
+	}
+}
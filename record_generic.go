@@ -0,0 +1,88 @@
+//go:build go1.18
+
+package airtable
+
+import "context"
+
+// RecordOf is a record whose fields are stored in a T, which must be a
+// struct that can be unmarshaled from the record's fields.
+type RecordOf[T any] struct {
+	Record
+	Fields T
+}
+
+// LinkedRecords holds the record IDs of a linked record field, like
+// RecordLink, but knows the type of the records it links to so they can
+// be fetched on demand with Fetch.
+type LinkedRecords[T any] []string
+
+// Fetch loads the linked records from table, which must be the table
+// the field links to. Records are requested in batches and returned in
+// the same order as the links; links to records that no longer exist
+// are skipped. Records in the client's RecordCache aren't fetched
+// again.
+func (l LinkedRecords[T]) Fetch(ctx context.Context, table Table) ([]RecordOf[T], error) {
+	return fetchLinked[T](ctx, table, l, table.client.RecordCache)
+}
+
+// TableNamer is implemented by the Fields types of the records a Link
+// can load. TableName returns the name or ID of the table they're in.
+type TableNamer interface {
+	TableName() string
+}
+
+// Link holds the record IDs of a linked record field, like
+// LinkedRecords, but the type of the linked records' fields says which
+// table they're in, so they can be loaded with just the client:
+//
+//	type Author struct{ Name string }
+//
+//	func (Author) TableName() string { return "Authors" }
+//
+//	type Book struct {
+//		Title   string
+//		Authors airtable.Link[Author]
+//	}
+type Link[T TableNamer] []string
+
+// Load fetches the linked records, as LinkedRecords.Fetch does. Records
+// are cached by the client, in its RecordCache if it has one or else
+// for DefaultLinkCacheTTL, so loading the links of every record in a
+// listing doesn't fetch the same records over and over.
+func (l Link[T]) Load(ctx context.Context, client *Client) ([]RecordOf[T], error) {
+	var fields T
+	table := client.Table(fields.TableName())
+	return fetchLinked[T](ctx, table, l, client.loadCache())
+}
+
+// fetchLinked fetches the records with the given IDs from table, in
+// order, using cache.
+func fetchLinked[T any](ctx context.Context, table Table, l []string, cache *RecordCache) ([]RecordOf[T], error) {
+	ids := make([]string, 0, len(l))
+	seen := make(map[string]bool, len(l))
+	for _, id := range l {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	raw, err := table.rawRecords(ctx, ids, cache)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]RecordOf[T], 0, len(l))
+	for _, id := range l {
+		data, ok := raw[id]
+		if !ok {
+			continue
+		}
+		var record RecordOf[T]
+		if err := table.client.decodeRecord(data, &record); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
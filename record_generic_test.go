@@ -0,0 +1,192 @@
+//go:build go1.18
+
+package airtable_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brianloveswords/airtable"
+)
+
+type author struct {
+	Name string
+}
+
+func TestLinkedRecordsFetch(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		want := `OR(RECORD_ID() = "recB", RECORD_ID() = "recA", RECORD_ID() = "recGONE")`
+		if got := r.URL.Query().Get("filterByFormula"); got != want {
+			t.Errorf("unexpected formula %s", got)
+		}
+		fmt.Fprint(w, `{"records": [
+			{"id": "recA", "fields": {"Name": "Ursula K. Le Guin"}},
+			{"id": "recB", "fields": {"Name": "Ted Chiang"}}
+		]}`)
+	})
+	defer done()
+
+	links := airtable.LinkedRecords[author]{"recB", "recA", "recGONE", "recB"}
+	authors, err := links.Fetch(context.Background(), client.Table("Authors"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, a := range authors {
+		names = append(names, a.Fields.Name)
+	}
+	want := "[Ted Chiang Ursula K. Le Guin Ted Chiang]"
+	if got := fmt.Sprint(names); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestLinkedRecordsFetchUsesCache(t *testing.T) {
+	var formulas []string
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v0/appTEST/Authors/recA" {
+			fmt.Fprint(w, `{"id": "recA", "fields": {"Name": "Ursula K. Le Guin"}}`)
+			return
+		}
+		formulas = append(formulas, r.URL.Query().Get("filterByFormula"))
+		fmt.Fprint(w, `{"records": [{"id": "recB", "fields": {"Name": "Ted Chiang"}}]}`)
+	})
+	defer done()
+	client.RecordCache = airtable.NewRecordCache(10, 0)
+
+	table := client.Table("Authors")
+	if err := table.Get("recA", &airtable.RecordOf[author]{}); err != nil {
+		t.Fatal(err)
+	}
+	links := airtable.LinkedRecords[author]{"recA", "recB"}
+	for i := 0; i < 2; i++ {
+		authors, err := links.Fetch(context.Background(), table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(authors) != 2 || authors[1].Fields.Name != "Ted Chiang" {
+			t.Errorf("unexpected authors %+v", authors)
+		}
+	}
+	if len(formulas) != 1 || formulas[0] != `RECORD_ID() = "recB"` {
+		t.Errorf("expected only recB to be fetched, once; got %v", formulas)
+	}
+}
+
+func TestTypedTable(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v0/appTEST/Authors":
+			fmt.Fprint(w, `{"records": [{"id": "recA", "fields": {"Name": "Ursula K. Le Guin"}}]}`)
+		case r.Method == "DELETE":
+			fmt.Fprint(w, `{"deleted": true, "id": "recA"}`)
+		default:
+			fmt.Fprint(w, `{"id": "recA", "fields": {"Name": "Ted Chiang"}}`)
+		}
+	})
+	defer done()
+
+	table, err := airtable.NewTable[author](client, "Authors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := table.List(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Fields.Name != "Ursula K. Le Guin" {
+		t.Fatalf("unexpected records %+v", records)
+	}
+	record, err := table.Get("recA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.ID != "recA" || record.Fields.Name != "Ted Chiang" {
+		t.Errorf("unexpected record %+v", record)
+	}
+	if err := table.Update(&record); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Delete(&record); err != nil {
+		t.Fatal(err)
+	}
+	if record.ID != "" {
+		t.Errorf("expected the deleted record to lose its ID, got %s", record.ID)
+	}
+}
+
+func TestNewTableChecksFields(t *testing.T) {
+	client := &airtable.Client{APIKey: "keyTEST", BaseID: "appTEST"}
+	var invalid airtable.ErrInvalidArg
+	if _, err := airtable.NewTable[int](client, "Numbers"); !errors.As(err, &invalid) || invalid.Arg != "T" {
+		t.Errorf("expected an ErrInvalidArg for T, got %v", err)
+	}
+	if _, err := airtable.NewTable[map[int]string](client, "Numbers"); err == nil {
+		t.Error("expected an error for a map without string keys")
+	}
+	if _, err := airtable.NewTable[map[string]interface{}](client, "Anything"); err != nil {
+		t.Errorf("expected a map with string keys to be allowed, got %v", err)
+	}
+}
+
+type linkedAuthor struct {
+	Name string
+}
+
+func (linkedAuthor) TableName() string { return "Authors" }
+
+func TestLinkLoad(t *testing.T) {
+	var paths []string
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		fmt.Fprint(w, `{"records": [
+			{"id": "recA", "fields": {"Name": "Ursula K. Le Guin"}},
+			{"id": "recB", "fields": {"Name": "Ted Chiang"}}
+		]}`)
+	})
+	defer done()
+
+	type book struct {
+		Title   string
+		Authors airtable.Link[linkedAuthor]
+	}
+	books := []book{
+		{"The Dispossessed", airtable.Link[linkedAuthor]{"recA", "recB"}},
+		{"Exhalation", airtable.Link[linkedAuthor]{"recB"}},
+	}
+	load := func() []string {
+		var names []string
+		for _, b := range books {
+			authors, err := b.Authors.Load(context.Background(), client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, a := range authors {
+				names = append(names, a.Fields.Name)
+			}
+		}
+		return names
+	}
+
+	// without a RecordCache, the client's own short-lived cache is used.
+	if names, want := load(), "[Ursula K. Le Guin Ted Chiang Ted Chiang]"; fmt.Sprint(names) != want {
+		t.Errorf("expected %s, got %v", want, names)
+	}
+	if len(paths) != 1 || paths[0] != "/v0/appTEST/Authors" {
+		t.Errorf("expected the authors to be fetched once, got %v", paths)
+	}
+
+	paths = nil
+	client.RecordCache = airtable.NewRecordCache(10, time.Minute)
+	if names, want := load(), "[Ursula K. Le Guin Ted Chiang Ted Chiang]"; fmt.Sprint(names) != want {
+		t.Errorf("expected %s, got %v", want, names)
+	}
+	if len(paths) != 1 {
+		t.Errorf("expected the authors to be fetched once into the RecordCache, got %v", paths)
+	}
+}
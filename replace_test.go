@@ -0,0 +1,30 @@
+package airtable_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestReplace(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		if r.Method != "PUT" || r.URL.Path != "/v0/appTEST/Tasks/rec1" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if want := `{"fields": {"Title":"a","Done":false}, "typecast": false}`; string(b) != want {
+			t.Errorf("unexpected body\n got: %s\nwant: %s", b, want)
+		}
+		fmt.Fprint(w, `{"id": "rec1", "fields": {"Title": "a"}}`)
+	})
+	defer done()
+
+	table := client.Table("Tasks")
+	task := taskRecord{}
+	task.ID = "rec1"
+	task.Fields.Title = "a"
+	if err := table.Replace(&task); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,38 @@
+package airtable
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// DefaultRequestIDHeader is the header the request ID is sent in when
+// the client doesn't set RequestIDHeader.
+var DefaultRequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying id as the request ID for
+// any requests made with it, so calls to Airtable can be correlated with
+// the work that caused them, e.g. an incoming HTTP request.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID set with WithRequestID.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// requestID returns the request ID from ctx, or generates a new one.
+func requestID(ctx context.Context) string {
+	if id, ok := RequestIDFromContext(ctx); ok {
+		return id
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
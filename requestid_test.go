@@ -0,0 +1,34 @@
+package airtable_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestRequestID(t *testing.T) {
+	var ids []string
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		ids = append(ids, r.Header.Get("X-Request-ID"))
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error": "NOT_FOUND"}`)
+	})
+	defer done()
+	table := client.Table("Books")
+
+	ctx := airtable.WithRequestID(context.Background(), "trace-123")
+	_, err := client.RequestContext(ctx, "GET", "Books/recX", nil)
+	reqErr, ok := err.(airtable.ErrClientRequest)
+	if !ok || reqErr.RequestID != "trace-123" || !strings.Contains(err.Error(), "trace-123") {
+		t.Errorf("expected error to carry request id, got %v", err)
+	}
+
+	table.Get("recX", &taskRecord{})
+	if len(ids) != 2 || ids[0] != "trace-123" || len(ids[1]) != 32 {
+		t.Errorf("expected the given and a generated request id, got %q", ids)
+	}
+}
@@ -0,0 +1,136 @@
+package airtable
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// FieldResolver maps between the names and IDs of a table's fields,
+// using the table's schema. Field IDs never change, so services that
+// refer to fields by ID, or rewrite their options to IDs with a
+// resolver, keep working when fields are renamed.
+type FieldResolver struct {
+	table *TableSchema
+}
+
+// FieldResolver returns a resolver for the table's fields, using the
+// client's cached schema (see Describe).
+func (t *Table) FieldResolver(ctx context.Context) (*FieldResolver, error) {
+	table, err := t.DescribeContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &FieldResolver{table: table}, nil
+}
+
+// ErrUnknownField is returned when a resolver is asked about a field
+// that isn't in the table.
+type ErrUnknownField struct {
+	Table string
+	Field string
+}
+
+func (e ErrUnknownField) Error() string {
+	return fmt.Sprintf("airtable: no field %s in table %s", e.Field, e.Table)
+}
+
+// ID returns the ID of the field with the given name or ID.
+func (r *FieldResolver) ID(nameOrID string) (string, error) {
+	field := r.table.Field(nameOrID)
+	if field == nil {
+		return "", ErrUnknownField{Table: r.table.Name, Field: nameOrID}
+	}
+	return field.ID, nil
+}
+
+// Name returns the current name of the field with the given ID or name.
+func (r *FieldResolver) Name(idOrName string) (string, error) {
+	field := r.table.Field(idOrName)
+	if field == nil {
+		return "", ErrUnknownField{Table: r.table.Name, Field: idOrName}
+	}
+	return field.Name, nil
+}
+
+// Options returns a copy of options with the fields in Fields, Sort,
+// SortBy and Filter referred to by ID, all sorts in SortBy. The names
+// must be Airtable field names, not the names of struct fields.
+func (r *FieldResolver) Options(options Options) (Options, error) {
+	rewritten := options
+	rewritten.Fields = nil
+	for _, name := range options.Fields {
+		id, err := r.ID(name)
+		if err != nil {
+			return options, err
+		}
+		rewritten.Fields = append(rewritten.Fields, id)
+	}
+	rewritten.Sort, rewritten.SortBy = nil, nil
+	for _, sort := range options.sortFields() {
+		id, err := r.ID(sort.Field)
+		if err != nil {
+			return options, err
+		}
+		rewritten.SortBy = append(rewritten.SortBy, SortField{id, sort.Direction})
+	}
+	filter, err := r.Formula(options.Filter)
+	if err != nil {
+		return options, err
+	}
+	rewritten.Filter = filter
+	return rewritten, nil
+}
+
+// Formula rewrites the field references in a formula, like {Title}, to
+// refer to the fields by ID, like {fldXXXXXXXXXXXXXX}. String literals
+// are left alone.
+func (r *FieldResolver) Formula(formula string) (string, error) {
+	var (
+		out   strings.Builder
+		quote rune
+	)
+	runes := []rune(formula)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case quote != 0:
+			out.WriteRune(c)
+			if c == '\\' && i+1 < len(runes) {
+				i++
+				out.WriteRune(runes[i])
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+			out.WriteRune(c)
+		case c == '{':
+			var name strings.Builder
+			closed := false
+			for i++; i < len(runes); i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+					name.WriteRune(runes[i])
+					continue
+				}
+				if runes[i] == '}' {
+					closed = true
+					break
+				}
+				name.WriteRune(runes[i])
+			}
+			if !closed {
+				return "", fmt.Errorf("airtable: unclosed field reference in formula %s", formula)
+			}
+			id, err := r.ID(name.String())
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(formulaField(id))
+		default:
+			out.WriteRune(c)
+		}
+	}
+	return out.String(), nil
+}
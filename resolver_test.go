@@ -0,0 +1,52 @@
+package airtable_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestFieldResolver(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, schemaResponse)
+	})
+	defer done()
+
+	table := client.Table("Books")
+	resolver, err := table.FieldResolver(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := resolver.ID("Genre"); err != nil || id != "fldGENRE" {
+		t.Errorf("unexpected ID %s (%v)", id, err)
+	}
+	if name, err := resolver.Name("fldGENRE"); err != nil || name != "Genre" {
+		t.Errorf("unexpected name %s (%v)", name, err)
+	}
+	if _, err := resolver.ID("Nope"); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+
+	options, err := resolver.Options(airtable.Options{
+		Fields: []string{"Title", "Score"},
+		Sort:   airtable.Sort{{"Score", airtable.SortDesc}},
+		Filter: `AND({Genre} = "{Title}", {fldSCORE} > 3)`,
+		View:   "Grid view",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(options.Fields) != "[fldTITLE fldSCORE]" || len(options.Sort) != 0 || options.SortBy[0].Field != "fldSCORE" || options.View != "Grid view" {
+		t.Errorf("unexpected options %+v", options)
+	}
+	if want := `AND({fldGENRE} = "{Title}", {fldSCORE} > 3)`; options.Filter != want {
+		t.Errorf("unexpected formula\n got: %s\nwant: %s", options.Filter, want)
+	}
+
+	if _, err := resolver.Formula("{Title"); err == nil {
+		t.Error("expected an error for an unclosed reference")
+	}
+}
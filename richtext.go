@@ -0,0 +1,280 @@
+package airtable
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// RichText is the value of a long text field with rich text formatting,
+// which Airtable sends and accepts as markdown: **bold**, _italic_,
+// ~~strikethrough~~, `code`, [links](https://example.com), headings,
+// quotes, lists, checklists and code blocks.
+//
+// Use PlainRichText to build rich text from text that shouldn't be
+// formatted, like user input, so characters like * and _ are kept as
+// they are.
+type RichText string
+
+// richTextEscapes are the characters that can be escaped with a
+// backslash in rich text.
+const richTextEscapes = "\\`*_~[]()#>-+."
+
+var (
+	richTextLinePrefix = regexp.MustCompile(`^(\s*)([#>\-+]|[0-9]+\.)`)
+	richTextHeading    = regexp.MustCompile(`^(#{1,6}) (.*)$`)
+	richTextQuote      = regexp.MustCompile(`^> ?(.*)$`)
+	richTextBullet     = regexp.MustCompile(`^\s*[-*+] (.*)$`)
+	richTextNumbered   = regexp.MustCompile(`^\s*[0-9]+\. (.*)$`)
+	richTextCheckbox   = regexp.MustCompile(`^\[([ xX])\] (.*)$`)
+	richTextLink       = regexp.MustCompile(`^\[([^\]]*)\]\(((?:[^()\s]|\([^()\s]*\))*)\)`)
+)
+
+// PlainRichText returns rich text that reads as s, with the characters
+// that would format it escaped.
+func PlainRichText(s string) RichText {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		var b strings.Builder
+		prefix := ""
+		if m := richTextLinePrefix.FindStringSubmatchIndex(line); m != nil {
+			// escape the marker's last character, e.g. "1\." or "\#".
+			prefix = line[:m[5]-1] + "\\" + line[m[5]-1:m[5]]
+			line = line[m[5]:]
+		}
+		for _, r := range line {
+			if r < 128 && strings.IndexByte("\\`*_~[]", byte(r)) >= 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		}
+		lines[i] = prefix + b.String()
+	}
+	return RichText(strings.Join(lines, "\n"))
+}
+
+// PlainText returns the text without its formatting. Headings and
+// quotes lose their markers, links are replaced by their text and
+// lists keep theirs.
+func (r RichText) PlainText() string {
+	var (
+		lines  []string
+		inCode bool
+	)
+	for _, line := range strings.Split(string(r), "\n") {
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			lines = append(lines, line)
+			continue
+		}
+		if m := richTextHeading.FindStringSubmatch(line); m != nil {
+			line = m[2]
+		} else if m := richTextQuote.FindStringSubmatch(line); m != nil {
+			line = m[1]
+		}
+		lines = append(lines, renderRichText(line, false))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HTML renders the text as HTML. Text is escaped and only http, https
+// and mailto links are kept, so it's safe to include in a page.
+func (r RichText) HTML() string {
+	var (
+		b      strings.Builder
+		block  string // the open block: "p", "ul", "ol" or "pre"
+		inPara bool
+	)
+	closeBlock := func() {
+		if block != "" {
+			if block == "pre" {
+				b.WriteString("</code></pre>\n")
+			} else {
+				b.WriteString("</" + block + ">\n")
+			}
+			block, inPara = "", false
+		}
+	}
+	openBlock := func(tag string) {
+		if block != tag {
+			closeBlock()
+			b.WriteString("<" + tag + ">")
+			if tag != "pre" && tag != "p" {
+				b.WriteString("\n")
+			}
+			block = tag
+		}
+	}
+	for _, line := range strings.Split(string(r), "\n") {
+		if strings.HasPrefix(line, "```") {
+			if block == "pre" {
+				closeBlock()
+			} else {
+				openBlock("pre")
+				b.WriteString("<code>")
+			}
+			continue
+		}
+		if block == "pre" {
+			b.WriteString(html.EscapeString(line) + "\n")
+			continue
+		}
+		if m := richTextHeading.FindStringSubmatch(line); m != nil {
+			closeBlock()
+			fmt.Fprintf(&b, "<h%d>%s</h%d>\n", len(m[1]), renderRichText(m[2], true), len(m[1]))
+		} else if m := richTextQuote.FindStringSubmatch(line); m != nil {
+			closeBlock()
+			fmt.Fprintf(&b, "<blockquote>%s</blockquote>\n", renderRichText(m[1], true))
+		} else if m := richTextBullet.FindStringSubmatch(line); m != nil {
+			openBlock("ul")
+			b.WriteString("<li>" + renderRichTextItem(m[1]) + "</li>\n")
+		} else if m := richTextNumbered.FindStringSubmatch(line); m != nil {
+			openBlock("ol")
+			b.WriteString("<li>" + renderRichTextItem(m[1]) + "</li>\n")
+		} else if strings.TrimSpace(line) == "" {
+			closeBlock()
+		} else {
+			if block == "p" && inPara {
+				b.WriteString("<br>\n")
+			}
+			openBlock("p")
+			inPara = true
+			b.WriteString(renderRichText(line, true))
+		}
+	}
+	closeBlock()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// renderRichTextItem renders a list item as HTML, with a checkbox if
+// it's a checklist item.
+func renderRichTextItem(item string) string {
+	m := richTextCheckbox.FindStringSubmatch(item)
+	if m == nil {
+		return renderRichText(item, true)
+	}
+	checkbox := `<input type="checkbox" disabled>`
+	if m[1] != " " {
+		checkbox = `<input type="checkbox" checked disabled>`
+	}
+	return checkbox + " " + renderRichText(m[2], true)
+}
+
+// richTextTags are the HTML tags of the inline formatting markers.
+var richTextTags = map[string]string{
+	"**": "strong",
+	"__": "strong",
+	"*":  "em",
+	"_":  "em",
+	"~~": "del",
+}
+
+// renderRichText renders the inline formatting of s as HTML, or strips
+// it if asHTML is false.
+func renderRichText(s string, asHTML bool) string {
+	var (
+		b    strings.Builder
+		open []string
+	)
+	text := func(t string) {
+		if asHTML {
+			t = html.EscapeString(t)
+		}
+		b.WriteString(t)
+	}
+	isOpen := func(marker string) bool {
+		return len(open) > 0 && open[len(open)-1] == marker
+	}
+	for i := 0; i < len(s); {
+		rest := s[i:]
+		marker := ""
+		for _, m := range []string{"**", "__", "~~", "*", "_"} {
+			if strings.HasPrefix(rest, m) {
+				marker = m
+				break
+			}
+		}
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && strings.IndexByte(richTextEscapes, rest[1]) >= 0:
+			text(rest[1:2])
+			i += 2
+		case rest[0] == '`' && strings.IndexByte(rest[1:], '`') >= 0:
+			end := strings.IndexByte(rest[1:], '`')
+			if asHTML {
+				b.WriteString("<code>" + html.EscapeString(rest[1:end+1]) + "</code>")
+			} else {
+				b.WriteString(rest[1 : end+1])
+			}
+			i += end + 2
+		case rest[0] == '[' && richTextLink.MatchString(rest):
+			m := richTextLink.FindStringSubmatch(rest)
+			if asHTML && safeLink(m[2]) {
+				fmt.Fprintf(&b, `<a href="%s">%s</a>`, html.EscapeString(m[2]), renderRichText(m[1], true))
+			} else if asHTML {
+				b.WriteString(renderRichText(m[1], true))
+			} else {
+				b.WriteString(renderRichText(m[1], false))
+			}
+			i += len(m[0])
+		case marker != "" && isOpen(marker):
+			if asHTML {
+				b.WriteString("</" + richTextTags[marker] + ">")
+			}
+			open = open[:len(open)-1]
+			i += len(marker)
+		case marker != "" && opensRichText(s, i, marker):
+			if asHTML {
+				b.WriteString("<" + richTextTags[marker] + ">")
+			}
+			open = append(open, marker)
+			i += len(marker)
+		default:
+			text(rest[:1])
+			i++
+		}
+	}
+	// close what's left open, e.g. when the closing marker was in code.
+	for asHTML && len(open) > 0 {
+		b.WriteString("</" + richTextTags[open[len(open)-1]] + ">")
+		open = open[:len(open)-1]
+	}
+	return b.String()
+}
+
+// safeLink reports whether a link's URL is safe to render as a link:
+// it's http, https or mailto, so links like javascript:alert(1) are
+// rendered as their text.
+func safeLink(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+// opensRichText reports whether the marker at s[i:] opens formatting:
+// it's followed by text and a closing marker, and underscores aren't in
+// the middle of a word, as in snake_case.
+func opensRichText(s string, i int, marker string) bool {
+	after := s[i+len(marker):]
+	if after == "" || after[0] == ' ' || !strings.Contains(after[1:], marker) {
+		return false
+	}
+	if marker[0] == '_' && i > 0 && isWordByte(s[i-1]) {
+		return false
+	}
+	return true
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,76 @@
+package airtable_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+const richText = "# Plan\n" +
+	"Ship **v2** _soon_, see [the doc](https://example.com/?a=1&b=2).\n" +
+	"Keep snake_case and `a*b` as is.\n" +
+	"\n" +
+	"- [x] write it\n" +
+	"- [ ] ~~test~~ it\n" +
+	"> quoted <script>\n" +
+	"```\n" +
+	"x := **y**\n" +
+	"```"
+
+func TestRichTextPlainText(t *testing.T) {
+	want := "Plan\n" +
+		"Ship v2 soon, see the doc.\n" +
+		"Keep snake_case and a*b as is.\n" +
+		"\n" +
+		"- [x] write it\n" +
+		"- [ ] test it\n" +
+		"quoted <script>\n" +
+		"x := **y**"
+	if got := airtable.RichText(richText).PlainText(); got != want {
+		t.Errorf("unexpected plain text\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestRichTextHTML(t *testing.T) {
+	want := "<h1>Plan</h1>\n" +
+		`<p>Ship <strong>v2</strong> <em>soon</em>, see <a href="https://example.com/?a=1&amp;b=2">the doc</a>.<br>` + "\n" +
+		"Keep snake_case and <code>a*b</code> as is.</p>\n" +
+		"<ul>\n" +
+		`<li><input type="checkbox" checked disabled> write it</li>` + "\n" +
+		`<li><input type="checkbox" disabled> <del>test</del> it</li>` + "\n" +
+		"</ul>\n" +
+		"<blockquote>quoted &lt;script&gt;</blockquote>\n" +
+		"<pre><code>x := **y**\n" +
+		"</code></pre>"
+	if got := airtable.RichText(richText).HTML(); got != want {
+		t.Errorf("unexpected HTML\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestRichTextUnsafeLinks(t *testing.T) {
+	text := airtable.RichText("[x](javascript:alert(1)) [y](JavaScript:alert%281%29) [z](data:text/html,hi) [ok](mailto:a@example.com)")
+	want := `<p>x y z <a href="mailto:a@example.com">ok</a></p>`
+	if got := text.HTML(); got != want {
+		t.Errorf("unexpected HTML\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestPlainRichText(t *testing.T) {
+	plain := "# not a heading\n1. not a list\n**not bold** and a_b_c [not](a link)"
+	text := airtable.PlainRichText(plain)
+	if got := text.PlainText(); got != plain {
+		t.Errorf("expected the plain text back\n got: %q\nwant: %q", got, plain)
+	}
+	if html := text.HTML(); html != "<p># not a heading<br>\n1. not a list<br>\n**not bold** and a_b_c [not](a link)</p>" {
+		t.Errorf("expected no formatting, got %q", html)
+	}
+
+	var cells struct{ Notes airtable.RichText }
+	if err := json.Unmarshal([]byte(`{"Notes": "**hi**\n"}`), &cells); err != nil {
+		t.Fatal(err)
+	}
+	if cells.Notes.PlainText() != "hi\n" {
+		t.Errorf("unexpected notes %q", cells.Notes.PlainText())
+	}
+}
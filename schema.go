@@ -0,0 +1,229 @@
+package airtable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultSchemaTTL is how long a client caches the base schema when it
+// doesn't set SchemaTTL.
+var DefaultSchemaTTL = 10 * time.Minute
+
+// BaseSchema is the structure of a base as described by the metadata
+// API: its tables, their fields and their views.
+type BaseSchema struct {
+	Tables []TableSchema `json:"tables"`
+}
+
+// TableSchema describes a table in a base.
+type TableSchema struct {
+	ID             string        `json:"id"`
+	Name           string        `json:"name"`
+	Description    string        `json:"description,omitempty"`
+	PrimaryFieldID string        `json:"primaryFieldId"`
+	Fields         []FieldSchema `json:"fields"`
+	Views          []ViewSchema  `json:"views"`
+}
+
+// FieldSchema describes a field in a table. Type is one of the field
+// types in the metadata API reference, e.g. "singleLineText",
+// "multipleRecordLinks" or "formula".
+type FieldSchema struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Type        string        `json:"type"`
+	Description string        `json:"description,omitempty"`
+	Options     *FieldOptions `json:"options,omitempty"`
+}
+
+// FieldOptions holds the type-specific options of a field. Only the
+// options relevant to the field's type are set.
+type FieldOptions struct {
+	// Choices of a singleSelect or multipleSelects field.
+	Choices []FieldChoice `json:"choices,omitempty"`
+
+	// LinkedTableID is the table a multipleRecordLinks field links to.
+	LinkedTableID string `json:"linkedTableId,omitempty"`
+
+	// Precision of a number, currency, percent or duration field.
+	Precision *int `json:"precision,omitempty"`
+
+	// Symbol of a currency field.
+	Symbol string `json:"symbol,omitempty"`
+
+	// Max of a rating field.
+	Max int `json:"max,omitempty"`
+
+	// ReferencedFieldIDs are the fields a formula uses, or the fields a
+	// last modified time or last modified by field watches. Empty for
+	// the latter when they watch every field.
+	ReferencedFieldIDs []string `json:"referencedFieldIds,omitempty"`
+
+	// Result describes the values of a formula, rollup, lookup or other
+	// computed field.
+	Result *FieldResult `json:"result,omitempty"`
+}
+
+// FieldChoice is one of the choices of a select field.
+type FieldChoice struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+}
+
+// FieldResult is the type of the values produced by a computed field.
+type FieldResult struct {
+	Type    string        `json:"type"`
+	Options *FieldOptions `json:"options,omitempty"`
+}
+
+// ViewSchema describes a view of a table. VisibleFieldIDs is only set
+// for grid views. The metadata API doesn't describe views' filters and
+// sorts; see Table.ViewOptions.
+type ViewSchema struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	Type            string   `json:"type"`
+	VisibleFieldIDs []string `json:"visibleFieldIds,omitempty"`
+}
+
+// Schema fetches the schema of the client's base from the metadata API.
+// The API key must have the schema.bases:read scope.
+func (c *Client) Schema(ctx context.Context) (*BaseSchema, error) {
+	c.checkSetup()
+
+	url := fmt.Sprintf("%s/%s/meta/bases/%s/tables?%s", c.RootURL, c.Version, c.BaseID,
+		esc("include[]")+"=visibleFieldIds")
+	bytes, err := c.doBytes(ctx, "GET", url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	schema := &BaseSchema{}
+	if err := json.Unmarshal(bytes, schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// Table returns the schema of the table with the given name or ID, or
+// nil if there is no such table.
+func (s *BaseSchema) Table(nameOrID string) *TableSchema {
+	for i := range s.Tables {
+		if t := &s.Tables[i]; t.Name == nameOrID || t.ID == nameOrID {
+			return t
+		}
+	}
+	return nil
+}
+
+// Field returns the schema of the field with the given name or ID, or
+// nil if there is no such field.
+func (t *TableSchema) Field(nameOrID string) *FieldSchema {
+	for i := range t.Fields {
+		if f := &t.Fields[i]; f.Name == nameOrID || f.ID == nameOrID {
+			return f
+		}
+	}
+	return nil
+}
+
+// View returns the schema of the view with the given name or ID, or nil
+// if there is no such view.
+func (t *TableSchema) View(nameOrID string) *ViewSchema {
+	for i := range t.Views {
+		if v := &t.Views[i]; v.Name == nameOrID || v.ID == nameOrID {
+			return v
+		}
+	}
+	return nil
+}
+
+// ViewOptions returns Options for listing exactly what the view shows:
+// its records, in its order, with only the fields visible in it. The
+// metadata API doesn't expose a view's filters and sorts, so those are
+// applied by Airtable through Options.View rather than translated into
+// Filter and Sort; the visible fields become Fields, using their
+// Airtable names. Views that aren't grids list every field.
+func (t *Table) ViewOptions(view string) (*Options, error) {
+	return t.ViewOptionsContext(context.Background(), view)
+}
+
+// ViewOptionsContext is like ViewOptions but the request for the schema,
+// if one is needed, is bound to ctx.
+func (t *Table) ViewOptionsContext(ctx context.Context, view string) (*Options, error) {
+	table, err := t.DescribeContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v := table.View(view)
+	if v == nil {
+		return nil, fmt.Errorf("airtable: no view %s in table %s", view, t.name)
+	}
+	options := &Options{View: v.ID}
+	for _, id := range v.VisibleFieldIDs {
+		if field := table.Field(id); field != nil {
+			options.Fields = append(options.Fields, field.Name)
+		}
+	}
+	return options, nil
+}
+
+// Describe returns the schema of the table: its fields and views. The
+// base schema is fetched from the metadata API and cached by the client
+// for SchemaTTL, so calling Describe often is cheap. Use
+// Client.InvalidateSchema to drop the cache after changing the schema.
+func (t *Table) Describe() (*TableSchema, error) {
+	return t.DescribeContext(context.Background())
+}
+
+// DescribeContext is like Describe but the request, if one is needed, is
+// bound to ctx.
+func (t *Table) DescribeContext(ctx context.Context) (*TableSchema, error) {
+	schema, err := t.client.cachedSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	table := schema.Table(t.name)
+	if table == nil {
+		return nil, fmt.Errorf("airtable: no table %s in base %s", t.name, t.client.BaseID)
+	}
+	return table, nil
+}
+
+// InvalidateSchema drops the client's cached schema so the next call
+// that needs it fetches it again.
+func (c *Client) InvalidateSchema() {
+	c.checkSetup()
+	c.schema.mu.Lock()
+	c.schema.schema = nil
+	c.schema.mu.Unlock()
+}
+
+// schemaCache holds a client's cached base schema.
+type schemaCache struct {
+	mu      sync.Mutex
+	schema  *BaseSchema
+	fetched time.Time
+}
+
+// cachedSchema returns the base schema, fetching it if it isn't cached
+// or the cache has expired.
+func (c *Client) cachedSchema(ctx context.Context) (*BaseSchema, error) {
+	c.checkSetup()
+	c.schema.mu.Lock()
+	defer c.schema.mu.Unlock()
+	if c.schema.schema != nil && time.Since(c.schema.fetched) < c.SchemaTTL {
+		return c.schema.schema, nil
+	}
+	schema, err := c.Schema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.schema.schema = schema
+	c.schema.fetched = time.Now()
+	return schema, nil
+}
@@ -0,0 +1,138 @@
+package airtable_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+const schemaResponse = `{"tables": [{
+	"id": "tblBOOKS",
+	"name": "Books",
+	"primaryFieldId": "fldTITLE",
+	"fields": [
+		{"id": "fldTITLE", "name": "Title", "type": "singleLineText"},
+		{"id": "fldGENRE", "name": "Genre", "type": "singleSelect",
+			"options": {"choices": [{"id": "sel1", "name": "Fantasy"}, {"id": "sel2", "name": "Sci-Fi"}]}},
+		{"id": "fldAUTHORS", "name": "Authors", "type": "multipleRecordLinks",
+			"options": {"linkedTableId": "tblAUTHORS"}},
+		{"id": "fldSCORE", "name": "Score", "type": "formula",
+			"options": {"result": {"type": "number", "options": {"precision": 1}}}}
+	],
+	"views": [{"id": "viwGRID", "name": "Grid view", "type": "grid", "visibleFieldIds": ["fldTITLE", "fldSCORE"]}]
+}]}`
+
+func TestSchema(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v0/meta/bases/appTEST/tables" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		fmt.Fprint(w, schemaResponse)
+	})
+	defer done()
+
+	schema, err := client.Schema(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	books := schema.Table("Books")
+	if books == nil {
+		t.Fatal("expected Books table")
+	}
+	if f := books.Field("fldAUTHORS"); f == nil || f.Options.LinkedTableID != "tblAUTHORS" {
+		t.Errorf("expected Authors to link to tblAUTHORS, got %+v", f)
+	}
+	if schema.Table("Nope") != nil {
+		t.Error("expected missing table to be nil")
+	}
+
+	b, err := json.Marshal(books.JSONSchema().Properties)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"Authors":{"title":"Authors","type":"array","items":{"type":"string","pattern":"^rec[a-zA-Z0-9]{14}$"}},` +
+		`"Genre":{"title":"Genre","type":"string","enum":["Fantasy","Sci-Fi"]},` +
+		`"Score":{"title":"Score","type":"number","readOnly":true},` +
+		`"Title":{"title":"Title","type":"string"}}`
+	if string(b) != want {
+		t.Errorf("unexpected JSON Schema\n got: %s\nwant: %s", b, want)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	requests := 0
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, schemaResponse)
+	})
+	defer done()
+
+	books := client.Table("Books")
+	for i := 0; i < 2; i++ {
+		table, err := books.Describe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if table.ID != "tblBOOKS" || len(table.Fields) != 4 || len(table.Views) != 1 {
+			t.Errorf("unexpected table schema %+v", table)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected schema to be cached, got %d requests", requests)
+	}
+
+	client.InvalidateSchema()
+	missing := client.Table("Missing")
+	if _, err := missing.Describe(); err == nil {
+		t.Error("expected an error for a table that isn't in the base")
+	}
+	if requests != 2 {
+		t.Errorf("expected schema to be fetched again, got %d requests", requests)
+	}
+}
+
+func TestViewOptions(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v0/meta/bases/appTEST/tables" {
+			if got := r.URL.Query().Get("include[]"); got != "visibleFieldIds" {
+				t.Errorf("expected visible fields to be asked for, got %q", got)
+			}
+			fmt.Fprint(w, schemaResponse)
+			return
+		}
+		query := r.URL.Query()
+		if query.Get("view") != "viwGRID" || query.Get("fields[0]") != "Title" || query.Get("fields[1]") != "Score" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"records": []}`)
+	})
+	defer done()
+
+	table := client.Table("Books")
+	options, err := table.ViewOptions("Grid view")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if options.View != "viwGRID" || len(options.Fields) != 2 {
+		t.Fatalf("unexpected options %+v", options)
+	}
+
+	// the Airtable names work with record types that name their fields
+	// differently.
+	records := []struct {
+		ID     string
+		Fields struct {
+			Name  string `json:"Title"`
+			Score float64
+		}
+	}{}
+	if err := table.List(&records, options); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := table.ViewOptions("Missing"); err == nil {
+		t.Error("expected an error for a view that isn't in the table")
+	}
+}
@@ -0,0 +1,219 @@
+package airtable
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SearchMode controls where Table.Search matches records.
+type SearchMode int
+
+// SearchServer builds a SEARCH() formula so only matching records are
+// sent by the API. SearchLocal fetches every record in the table and
+// matches them in the client, which is slower but matches exactly the
+// values the record struct holds.
+const (
+	SearchServer SearchMode = iota
+	SearchLocal
+)
+
+// Search finds the records where query appears, ignoring case, in any
+// of fields and appends them to the slice pointed to by listPtr, most
+// relevant first. Records are ranked by how many times query appears
+// across fields. fields name the fields to search like in
+// Options.Fields, by their names in the record struct, their Airtable
+// names or their IDs, which are looked up in the table's schema; if
+// empty, every field is searched. Records whose Fields isn't a struct
+// must name the fields to search, and with a json.RawMessage only
+// SearchServer finds anything.
+//
+// listPtr has the same requirements as in List.
+func (t *Table) Search(query string, fields []string, listPtr interface{}, mode SearchMode) error {
+	if err := t.argError(checkListArg(listPtr)); err != nil {
+		return err
+	}
+
+	typ := getRecordType(listPtr)
+	if len(fields) == 0 {
+		fields = recordFieldNames(typ)
+	}
+	if len(fields) == 0 {
+		return t.argError(ErrInvalidArg{Arg: "fields", Reason: fmt.Sprintf("is empty and there are no fields to search in %s", typ)})
+	}
+	for _, name := range fields {
+		if _, err := lookupFieldName(name, typ); err != nil {
+			return t.argError(ErrInvalidArg{Arg: "fields", Reason: fmt.Sprintf("names %s, no such field in %s", name, typ)})
+		}
+	}
+
+	index, err := t.searchFieldIndex(fields, typ)
+	if err != nil {
+		return err
+	}
+
+	options := &Options{}
+	if mode == SearchServer {
+		options.setType(typ)
+		options.Filter = searchFormula(query, fields, typ)
+	}
+	found := reflect.New(reflect.TypeOf(listPtr).Elem())
+	if err := t.List(found.Interface(), options); err != nil {
+		return err
+	}
+
+	type match struct {
+		record reflect.Value
+		score  int
+	}
+	var (
+		records = found.Elem()
+		matches = make([]match, 0, records.Len())
+	)
+	for i := 0; i < records.Len(); i++ {
+		record := records.Index(i)
+		score := searchScore(query, fields, index, record.FieldByName("Fields"))
+		// the server and the client can render a value differently
+		// (e.g. dates), so server matches are kept even if they don't
+		// match locally.
+		if score == 0 && mode == SearchLocal {
+			continue
+		}
+		matches = append(matches, match{record, score})
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	list := reflect.ValueOf(listPtr).Elem()
+	for _, m := range matches {
+		list.Set(reflect.Append(list, m.record))
+	}
+	return nil
+}
+
+// searchFormula builds a formula that matches records where query
+// appears in any of fields, ignoring case.
+func searchFormula(query string, fields []string, typ reflect.Type) string {
+	formulas := make([]string, len(fields))
+	for i, name := range fields {
+		formulas[i] = fmt.Sprintf("SEARCH(LOWER(%s), LOWER(%s & \"\"))",
+			formulaString(query),
+			formulaField(getFieldJSONName(name, typ)),
+		)
+	}
+	return formulaOr(formulas)
+}
+
+// searchFieldIndex resolves the names of the fields to search, which
+// can be Go names, Airtable names or field IDs, to the indexes of the
+// fields in the Fields struct of the record type typ, or -1 for names
+// that aren't fields of the struct. Field IDs are looked up in the
+// table's schema. Records whose Fields isn't a struct have no indexes.
+func (t *Table) searchFieldIndex(names []string, typ reflect.Type) ([]int, error) {
+	fields, _ := typ.FieldByName("Fields")
+	if fields.Type.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	var schema *TableSchema
+	index := make([]int, len(names))
+	for i, name := range names {
+		index[i] = -1
+		if isFieldID(name) {
+			if schema == nil {
+				var err error
+				if schema, err = t.Describe(); err != nil {
+					return nil, err
+				}
+			}
+			field := schema.Field(name)
+			if field == nil {
+				continue
+			}
+			name = field.Name
+		}
+		for j := 0; j < fields.Type.NumField(); j++ {
+			f := fields.Type.Field(j)
+			if f.PkgPath != "" {
+				continue
+			}
+			if airtableName, ok := fieldName(f); ok && (f.Name == name || airtableName == name) {
+				index[i] = j
+				break
+			}
+		}
+	}
+	return index, nil
+}
+
+// searchScore counts the times query appears in the named fields of the
+// Fields struct, ignoring case. index holds the indexes of the fields
+// in a Fields struct, as from searchFieldIndex.
+func searchScore(query string, names []string, index []int, fields reflect.Value) int {
+	if query == "" {
+		return 0
+	}
+	query = strings.ToLower(query)
+	score := 0
+	for i, name := range names {
+		var cell reflect.Value
+		switch fields.Kind() {
+		case reflect.Map:
+			cell = fields.MapIndex(reflect.ValueOf(name).Convert(fields.Type().Key()))
+		case reflect.Struct:
+			if index[i] >= 0 {
+				cell = fields.Field(index[i])
+			}
+		}
+		text := strings.ToLower(cellText(cell))
+		score += strings.Count(text, query)
+	}
+	return score
+}
+
+// cellText renders a field value as text to search in.
+func cellText(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Slice, reflect.Array:
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = cellText(v.Index(i))
+		}
+		return strings.Join(parts, ", ")
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return ""
+		}
+		return cellText(v.Elem())
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// recordFieldNames lists the Airtable names of the exported fields in
+// the Fields struct of the record type typ, leaving out fields tagged
+// json:"-" or airtable:"-", which aren't in Airtable. Records with map
+// or raw Fields have none.
+func recordFieldNames(typ reflect.Type) []string {
+	fields, _ := typ.FieldByName("Fields")
+	if fields.Type.Kind() != reflect.Struct {
+		return nil
+	}
+	var names []string
+	for i := 0; i < fields.Type.NumField(); i++ {
+		f := fields.Type.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		if name, ok := fieldName(f); ok && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,126 @@
+package airtable_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+type searchRecord struct {
+	airtable.Record
+	Fields struct {
+		Title string `json:"Book Title,omitempty"`
+		Notes string
+	}
+}
+
+const searchResponse = `{"records": [
+	{"id": "rec1", "fields": {"Book Title": "Dune", "Notes": "sand"}},
+	{"id": "rec2", "fields": {"Book Title": "Sand and Sandworms", "Notes": "more sand"}},
+	{"id": "rec3", "fields": {"Book Title": "Emma", "Notes": "no match"}}
+]}`
+
+func TestSearchServer(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		want := `OR(SEARCH(LOWER("Sand"), LOWER({Book Title} & "")), SEARCH(LOWER("Sand"), LOWER({Notes} & "")))`
+		if got := r.URL.Query().Get("filterByFormula"); got != want {
+			t.Errorf("unexpected formula %s", got)
+		}
+		fmt.Fprint(w, searchResponse)
+	})
+	defer done()
+
+	table := client.Table("Books")
+	results := []searchRecord{}
+	if err := table.Search("Sand", nil, &results, airtable.SearchServer); err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, r := range results {
+		ids = append(ids, r.ID)
+	}
+	if got := fmt.Sprint(ids); got != "[rec2 rec1 rec3]" {
+		t.Errorf("expected records ranked by matches, got %s", got)
+	}
+}
+
+func TestSearchLocal(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("filterByFormula"); got != "" {
+			t.Errorf("expected no formula, got %s", got)
+		}
+		fmt.Fprint(w, searchResponse)
+	})
+	defer done()
+
+	table := client.Table("Books")
+	results := []searchRecord{}
+	if err := table.Search("SAND", []string{"Title"}, &results, airtable.SearchLocal); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].ID != "rec2" {
+		t.Errorf("expected only rec2 to match, got %+v", results)
+	}
+}
+
+func TestSearchLocalRenamedField(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/meta/") {
+			fmt.Fprint(w, `{"tables": [{"id": "tblPEOPLE", "name": "People", "fields": [
+				{"id": "fldNAME0000000000", "name": "Full Name", "type": "singleLineText"}
+			]}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"records": [
+			{"id": "rec1", "fields": {"Full Name": "Ada Lovelace", "Bio": "mathematician"}},
+			{"id": "rec2", "fields": {"Full Name": "Charles Babbage", "Bio": "worked with Ada"}}
+		]}`)
+	})
+	defer done()
+
+	type person struct {
+		airtable.Record
+		Fields struct {
+			Name string `airtable:"Full Name"`
+			Bio  string
+		}
+	}
+	table := client.Table("People")
+	for _, field := range []string{"Name", "Full Name", "fldNAME0000000000"} {
+		results := []person{}
+		if err := table.Search("ada", []string{field}, &results, airtable.SearchLocal); err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 || results[0].ID != "rec1" {
+			t.Errorf("expected only rec1 to match %s, got %+v", field, results)
+		}
+	}
+}
+
+func TestSearchSkipsIgnoredFields(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		want := `SEARCH(LOWER("x"), LOWER({Full Name} & ""))`
+		if got := r.URL.Query().Get("filterByFormula"); got != want {
+			t.Errorf("unexpected formula %s", got)
+		}
+		fmt.Fprint(w, `{"records": []}`)
+	})
+	defer done()
+
+	type person struct {
+		airtable.Record
+		Fields struct {
+			Name    string `airtable:"Full Name"`
+			Scratch string `json:"-"`
+			Cached  string `airtable:"-"`
+		}
+	}
+	table := client.Table("People")
+	results := []person{}
+	if err := table.Search("x", nil, &results, airtable.SearchServer); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,84 @@
+package airtable
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// flightGroup coalesces concurrent calls with the same key into one.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight is a call in progress, or just finished, for a key.
+type flight struct {
+	done    chan struct{}
+	bytes   []byte
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// do calls fn, unless a call for key is already in progress, in which
+// case it waits for that call and returns its result instead. Each
+// caller gets its own copy of the bytes.
+//
+// fn isn't bound to the context of the caller that started it, since
+// the others are waiting for it too: it's given a context with the same
+// values that's only cancelled once every caller waiting for it has
+// given up. A caller whose ctx is done stops waiting and gets ctx.Err().
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	if g.flights == nil {
+		g.flights = map[string]*flight{}
+	}
+	f, ok := g.flights[key]
+	if !ok {
+		fctx, cancel := context.WithCancel(detachedContext{ctx})
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		g.flights[key] = f
+		go func() {
+			f.bytes, f.err = fn(fctx)
+			g.mu.Lock()
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+			g.mu.Unlock()
+			cancel()
+			close(f.done)
+		}()
+	}
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return append([]byte(nil), f.bytes...), f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// nobody is left to get the result, and later callers
+			// shouldn't join a call that's being cancelled.
+			f.cancel()
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+		}
+		g.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// detachedContext has the values of the context it wraps but is never
+// done, so a call can outlive the caller that made it.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}               { return nil }
+func (detachedContext) Err() error                          { return nil }
+func (c detachedContext) Value(key interface{}) interface{} { return c.parent.Value(key) }
@@ -0,0 +1,105 @@
+package airtable_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCoalesceGETs(t *testing.T) {
+	var (
+		requests int32
+		release  = make(chan struct{})
+	)
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		fmt.Fprint(w, `{"id": "rec1", "fields": {"Title": "Write tests"}}`)
+	})
+	defer done()
+	client.CoalesceGETs = true
+
+	var wg sync.WaitGroup
+	records := make([]taskRecord, 5)
+	for i := range records {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			table := client.Table("Tasks")
+			if err := table.Get("rec1", &records[i]); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	// wait for the first request to arrive, then give the others time
+	// to join it before letting it finish.
+	for atomic.LoadInt32(&requests) == 0 {
+		runtime.Gosched()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+	for _, record := range records {
+		if record.Fields.Title != "Write tests" {
+			t.Errorf("expected every caller to get the record, got %+v", record)
+		}
+	}
+}
+
+func TestCoalesceGETsFirstCallerCancels(t *testing.T) {
+	var (
+		requests int32
+		release  = make(chan struct{})
+	)
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		fmt.Fprint(w, `{"id": "rec1", "fields": {"Title": "Write tests"}}`)
+	})
+	defer done()
+	client.CoalesceGETs = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := client.RequestContext(ctx, "GET", "Tasks/rec1", nil)
+		first <- err
+	}()
+	for atomic.LoadInt32(&requests) == 0 {
+		runtime.Gosched()
+	}
+	second := make(chan error)
+	go func() {
+		_, err := client.RequestContext(context.Background(), "GET", "Tasks/rec1", nil)
+		second <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// the first caller giving up doesn't fail the request for the
+	// second.
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the first caller to be canceled, got %v", err)
+	}
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("expected the second caller to get the record, got %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+}
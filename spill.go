@@ -0,0 +1,177 @@
+package airtable
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+)
+
+// responseBody is the body of a response: in memory, in a temporary
+// file if it was larger than the client's MaxResponseSize, or still
+// being read from the connection if it's a page of records that's
+// decoded as it arrives (see withStreamedBody).
+type responseBody struct {
+	data   []byte
+	file   *os.File
+	stream io.ReadCloser
+}
+
+type streamedBodyKey struct{}
+
+// withStreamedBody returns a context for requests whose successful
+// responses are returned unread, for the caller to decode from the
+// connection. Error responses are still read in full to check them.
+func withStreamedBody(ctx context.Context) context.Context {
+	return context.WithValue(ctx, streamedBodyKey{}, true)
+}
+
+// streamedBody reports whether ctx is from withStreamedBody.
+func streamedBody(ctx context.Context) bool {
+	streamed, _ := ctx.Value(streamedBodyKey{}).(bool)
+	return streamed
+}
+
+// readBody reads the response body. Up to limit bytes are read into a
+// pooled buffer and copied out; if the body is any larger, it's written
+// to a temporary file instead. A limit of 0 means no limit.
+func readBody(resp *http.Response, limit int64) (*responseBody, error) {
+	buf := bodyBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBuffer {
+			bodyBuffers.Put(buf)
+		}
+	}()
+	if limit <= 0 {
+		if resp.ContentLength > 0 && resp.ContentLength <= maxPooledBuffer {
+			buf.Grow(int(resp.ContentLength))
+		}
+		if _, err := buf.ReadFrom(resp.Body); err != nil {
+			return nil, err
+		}
+		return &responseBody{data: append([]byte(nil), buf.Bytes()...)}, nil
+	}
+
+	if resp.ContentLength > 0 && resp.ContentLength <= limit && resp.ContentLength <= maxPooledBuffer {
+		buf.Grow(int(resp.ContentLength))
+	}
+	n, err := buf.ReadFrom(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if n <= limit {
+		return &responseBody{data: append([]byte(nil), buf.Bytes()...)}, nil
+	}
+
+	file, err := ioutil.TempFile("", "airtable-response-")
+	if err != nil {
+		return nil, err
+	}
+	body := &responseBody{file: file}
+	if _, err := buf.WriteTo(file); err != nil {
+		body.close()
+		return nil, err
+	}
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		body.close()
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		body.close()
+		return nil, err
+	}
+	return body, nil
+}
+
+// reader returns the reader to decode the body from if it isn't in
+// memory, or nil if it is.
+func (b *responseBody) reader() io.Reader {
+	switch {
+	case b == nil:
+		return nil
+	case b.file != nil:
+		return b.file
+	case b.stream != nil:
+		return b.stream
+	}
+	return nil
+}
+
+// bytes returns the whole body, reading it back from the temporary file
+// if it was spilled or from the connection if it was streamed. The file
+// is removed, or the connection closed.
+func (b *responseBody) bytes() ([]byte, error) {
+	r := b.reader()
+	if r == nil {
+		if b == nil {
+			return nil, nil
+		}
+		return b.data, nil
+	}
+	defer b.close()
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	b.data = data
+	return data, nil
+}
+
+// readAll returns the whole body of a response along with the error the
+// request returned, or the error reading the body back if there wasn't
+// one.
+func readAll(b *responseBody, err error) ([]byte, error) {
+	bytes, readErr := b.bytes()
+	if err == nil {
+		err = readErr
+	}
+	return bytes, err
+}
+
+// close removes the temporary file or closes the connection, if there
+// is one. It's safe to call on a nil body, and more than once.
+func (b *responseBody) close() {
+	if b == nil {
+		return
+	}
+	if b.file != nil {
+		b.file.Close()
+		os.Remove(b.file.Name())
+		b.file = nil
+	}
+	if b.stream != nil {
+		b.stream.Close()
+		b.stream = nil
+	}
+}
+
+// checkError returns the error in the response, if there is one.
+// Airtable's error responses are small, so a spilled body is only read
+// back to look for one if the status says the request failed.
+func (b *responseBody) checkError(status int) error {
+	if b.file == nil {
+		return checkErrorResponse(b.data, status)
+	}
+	if status < 400 {
+		return nil
+	}
+	data, err := b.bytes()
+	if err != nil {
+		return err
+	}
+	return checkErrorResponse(data, status)
+}
+
+// readFailed reports whether err, from decoding a streamed body, is
+// because the body couldn't be read rather than because it couldn't be
+// decoded.
+func readFailed(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &netErr) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
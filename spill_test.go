@@ -0,0 +1,62 @@
+package airtable_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestListSpillsLargePages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airtable-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	tmpdir := os.Getenv("TMPDIR")
+	os.Setenv("TMPDIR", dir)
+	defer os.Setenv("TMPDIR", tmpdir)
+
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "" {
+			fmt.Fprint(w, `{"records": [{"id": "rec1", "fields": {"Title": "a", "Done": true}}, {"id": "rec2", "fields": {"Title": "b"}}], "offset": "page2"}`)
+			return
+		}
+		fmt.Fprint(w, `{"records": [{"id": "rec3", "fields": {"Title": "c"}}]}`)
+	})
+	defer done()
+	client.MaxResponseSize = 64
+	client.KeepFieldsPresent = true
+
+	// pages are only read whole, and so spilled, when prefetching.
+	table := client.Table("Books")
+	records := []taskRecord{}
+	if err := table.List(&records, &airtable.Options{Prefetch: true}); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[2].ID != "rec3" || records[2].Fields.Title != "c" || !records[0].Fields.Done {
+		t.Errorf("unexpected records %+v", records)
+	}
+	if present := records[1].FieldsPresent(); !present["Title"] || present["Done"] {
+		t.Errorf("unexpected fields present %v", present)
+	}
+
+	type plainTask struct {
+		ID     string
+		Fields struct{ Title string }
+	}
+	plain := []plainTask{}
+	if err := table.List(&plain, &airtable.Options{Prefetch: true}); err != nil {
+		t.Fatal(err)
+	}
+	if len(plain) != 3 || plain[1].ID != "rec2" || plain[1].Fields.Title != "b" {
+		t.Errorf("unexpected records %+v", plain)
+	}
+
+	if files, _ := ioutil.ReadDir(dir); len(files) != 0 {
+		t.Errorf("expected temporary files to be removed, found %d", len(files))
+	}
+}
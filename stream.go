@@ -0,0 +1,126 @@
+//go:build go1.18
+
+package airtable
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultStreamBuffer is the number of records a Stream buffers when
+// NewStream is called with a buffer of 0. It's one full page.
+var DefaultStreamBuffer = 100
+
+// Stream delivers the records of a table over a channel as the pages
+// are fetched, instead of collecting the whole table in memory like
+// List does.
+//
+// The channel is bounded: when it's full the stream stops fetching
+// pages until the consumer catches up, so at most the buffer plus one
+// page of records is held in memory however slow the consumer is.
+type Stream[T any] struct {
+	records chan RecordOf[T]
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+// NewStream starts streaming the records of table matching options.
+// buffer is the number of records held for the consumer before
+// fetching pauses; if it's 0, DefaultStreamBuffer is used. The stream
+// stops when every page has been delivered, when fetching a page
+// fails, when ctx is done or when Close is called.
+func NewStream[T any](ctx context.Context, table Table, options *Options, buffer int) *Stream[T] {
+	if buffer <= 0 {
+		buffer = DefaultStreamBuffer
+	}
+	opts := Options{}
+	if options != nil {
+		opts = *options
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Stream[T]{
+		records: make(chan RecordOf[T], buffer),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	// invalid options stop the stream before it starts, rather than
+	// panicking in its goroutine.
+	opts.setType(getRecordType(&[]RecordOf[T]{}))
+	if err := table.argError(opts.check()); err != nil {
+		s.fail(err)
+		cancel()
+		close(s.records)
+		close(s.done)
+		return s
+	}
+	go s.run(ctx, table, &opts)
+	return s
+}
+
+// Records returns the channel the records are delivered on. It's
+// closed when the stream stops; check Err afterwards to see why.
+func (s *Stream[T]) Records() <-chan RecordOf[T] {
+	return s.records
+}
+
+// Err returns the error that stopped the stream, or nil if the whole
+// table was delivered. It's only meaningful once Records is closed.
+func (s *Stream[T]) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close stops the stream and waits for it to finish. Records that were
+// buffered but not yet received are dropped. It's safe to call Close
+// more than once, and after the stream has already stopped.
+func (s *Stream[T]) Close() {
+	s.cancel()
+	<-s.done
+}
+
+func (s *Stream[T]) run(ctx context.Context, table Table, options *Options) {
+	defer close(s.done)
+	defer close(s.records)
+	defer s.cancel()
+
+	var page []RecordOf[T]
+	options.setType(getRecordType(&page))
+	for {
+		body, err := table.client.requestPage(withStreamedBody(ctx), table.makePath(""), options)
+		if err != nil {
+			body.close()
+			s.fail(listError(options.Offset, err))
+			return
+		}
+		page = page[:0]
+		offset, err := table.client.newListDecoder(&page).read(body)
+		body.close()
+		if err != nil {
+			s.fail(err)
+			return
+		}
+		for _, record := range page {
+			select {
+			case s.records <- record:
+			case <-ctx.Done():
+				s.fail(ctx.Err())
+				return
+			}
+		}
+		options.Offset = offset
+		if options.Offset == "" {
+			return
+		}
+	}
+}
+
+func (s *Stream[T]) fail(err error) {
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
@@ -0,0 +1,64 @@
+//go:build go1.18
+
+package airtable_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestStream(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("offset") {
+		case "":
+			fmt.Fprint(w, recordsResponse(0, 3, "page2"))
+		case "page2":
+			fmt.Fprint(w, recordsResponse(3, 2, ""))
+		default:
+			t.Errorf("unexpected offset %s", r.URL.Query().Get("offset"))
+		}
+	})
+	defer done()
+
+	stream := airtable.NewStream[map[string]interface{}](context.Background(), client.Table("Tasks"), nil, 1)
+	defer stream.Close()
+
+	var ids []string
+	for record := range stream.Records() {
+		ids = append(ids, record.ID)
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 5 {
+		t.Errorf("expected 5 records, got %v", ids)
+	}
+}
+
+func TestStreamPausesForSlowConsumer(t *testing.T) {
+	var pages int32
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&pages, 1)
+		fmt.Fprint(w, recordsResponse(int(n)*2, 2, fmt.Sprintf("page%d", n+1)))
+	})
+	defer done()
+
+	stream := airtable.NewStream[map[string]interface{}](context.Background(), client.Table("Tasks"), nil, 1)
+	<-stream.Records()
+	stream.Close()
+
+	// one page is buffered and one is waiting to be sent, so the
+	// stream can't have fetched more than two pages.
+	if n := atomic.LoadInt32(&pages); n > 2 {
+		t.Errorf("expected fetching to pause, fetched %d pages", n)
+	}
+	if !errors.Is(stream.Err(), context.Canceled) {
+		t.Errorf("expected the stream to stop with context.Canceled, got %v", stream.Err())
+	}
+}
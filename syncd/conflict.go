@@ -0,0 +1,269 @@
+package syncd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"time"
+)
+
+// maxPushBatch is the most records Airtable accepts in one update.
+const maxPushBatch = 10
+
+// Change is an edit made to a record in the destination that should be
+// pushed to Airtable.
+//
+// - ID: the record that was edited. Only edits to records that already
+// exist in Airtable can be pushed.
+//
+// - Fields: the cells that were edited. Computed fields, such as the
+// table's modified field, must be left out.
+//
+// - Modified: when the edit was made, for LastWriterWins.
+type Change struct {
+	ID       string
+	Fields   map[string]json.RawMessage
+	Modified time.Time
+}
+
+// ChangeSource is implemented by destinations that accept local edits.
+// If the destination is a ChangeSource, each pass pushes its changes to
+// Airtable, resolving conflicts with the Syncer's ConflictPolicy or
+// Resolve function.
+type ChangeSource interface {
+	// Changes returns the edits to the table that haven't been pushed.
+	Changes(ctx context.Context, table string) ([]Change, error)
+
+	// Pushed is called with the IDs of changes that have been dealt
+	// with: either written to Airtable, or discarded because the
+	// Airtable edit won the conflict or the record was deleted. They
+	// shouldn't be returned by Changes again.
+	Pushed(ctx context.Context, table string, ids []string) error
+}
+
+// ConflictPolicy decides which edit wins when a record was changed both
+// in the destination and in Airtable since the last pass.
+type ConflictPolicy int
+
+const (
+	// LastWriterWins keeps the most recent edit, comparing the change's
+	// Modified time to the record's modified field. If the table has no
+	// modified field the local edit wins.
+	LastWriterWins ConflictPolicy = iota
+
+	// PreferLocal always keeps the edit made in the destination.
+	PreferLocal
+
+	// PreferRemote always keeps the edit made in Airtable.
+	PreferRemote
+)
+
+// Conflict is a record that was edited in both places.
+//
+// - Table: name of the table.
+//
+// - Local: the edit made in the destination.
+//
+// - Remote: the record as it is in Airtable.
+//
+// - RemoteModified: when the record was last modified in Airtable, or
+// the zero time if the table has no modified field.
+type Conflict struct {
+	Table          string
+	Local          Change
+	Remote         Record
+	RemoteModified time.Time
+}
+
+// Resolver resolves a conflict by returning the cells to write to
+// Airtable, for example a merge of the local and remote edits. If it
+// returns no fields, the Airtable edit wins and the local edit is
+// discarded.
+type Resolver func(ctx context.Context, conflict Conflict) (map[string]json.RawMessage, error)
+
+// resolve applies the policy to the conflict.
+func (p ConflictPolicy) resolve(conflict Conflict) map[string]json.RawMessage {
+	switch p {
+	case PreferLocal:
+		return conflict.Local.Fields
+	case PreferRemote:
+		return nil
+	default:
+		if conflict.Local.Modified.Before(conflict.RemoteModified) {
+			return nil
+		}
+		return conflict.Local.Fields
+	}
+}
+
+// localChanges returns the destination's changes to the table by ID, or
+// nil if the destination isn't a ChangeSource.
+func (s *Syncer) localChanges(ctx context.Context, table string) (map[string]Change, error) {
+	source, ok := s.Destination.(ChangeSource)
+	if !ok {
+		return nil, nil
+	}
+	changes, err := source.Changes(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]Change, len(changes))
+	for _, c := range changes {
+		byID[c.ID] = c
+	}
+	return byID, nil
+}
+
+// resolveConflicts goes through a page of records from Airtable and
+// returns the ones to write to the destination. Records with a pending
+// change are held back, since the change will be pushed and the result
+// written instead, unless the Airtable edit wins the conflict, in which
+// case the change is discarded.
+func (s *Syncer) resolveConflicts(
+	ctx context.Context,
+	table Table,
+	since time.Time,
+	records []Record,
+	changes map[string]Change,
+) ([]Record, error) {
+	keep := records[:0:0]
+	var discarded []string
+	for _, r := range records {
+		change, ok := changes[r.ID]
+		if !ok {
+			keep = append(keep, r)
+			continue
+		}
+		modified := modifiedTime(r, table.ModifiedField)
+		if !modified.IsZero() && !modified.After(since) {
+			// not edited in Airtable since the last pass, so it's not
+			// a conflict.
+			continue
+		}
+		conflict := Conflict{
+			Table:          table.Name,
+			Local:          change,
+			Remote:         r,
+			RemoteModified: modified,
+		}
+		var fields map[string]json.RawMessage
+		if s.Resolve != nil {
+			var err error
+			if fields, err = s.Resolve(ctx, conflict); err != nil {
+				return nil, err
+			}
+		} else {
+			fields = s.ConflictPolicy.resolve(conflict)
+		}
+		if len(fields) == 0 {
+			delete(changes, r.ID)
+			discarded = append(discarded, r.ID)
+			keep = append(keep, r)
+			continue
+		}
+		change.Fields = fields
+		changes[r.ID] = change
+	}
+	if len(discarded) > 0 {
+		if err := s.Destination.(ChangeSource).Pushed(ctx, table.Name, discarded); err != nil {
+			return nil, err
+		}
+	}
+	return keep, nil
+}
+
+// push writes the pending changes to Airtable and the updated records
+// back to the destination. After a full load, changes to records that
+// weren't seen are discarded since the records were deleted.
+func (s *Syncer) push(
+	ctx context.Context,
+	table string,
+	changes map[string]Change,
+	seen map[string]bool,
+	full bool,
+) error {
+	source := s.Destination.(ChangeSource)
+	var (
+		ids     []string
+		deleted []string
+	)
+	for id := range changes {
+		if full && !seen[id] {
+			deleted = append(deleted, id)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(deleted) > 0 {
+		if err := source.Pushed(ctx, table, deleted); err != nil {
+			return err
+		}
+	}
+
+	for start := 0; start < len(ids); start += maxPushBatch {
+		end := start + maxPushBatch
+		if end > len(ids) {
+			end = len(ids)
+		}
+		batch := ids[start:end]
+		records, err := s.update(ctx, table, batch, changes)
+		if err != nil {
+			return err
+		}
+		if err := s.Destination.Upsert(ctx, table, records); err != nil {
+			return err
+		}
+		for _, r := range records {
+			seen[r.ID] = true
+			s.written[table][r.ID] = true
+		}
+		if err := source.Pushed(ctx, table, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// update sends one batch of changes to Airtable and returns the updated
+// records.
+func (s *Syncer) update(ctx context.Context, table string, ids []string, changes map[string]Change) ([]Record, error) {
+	type update struct {
+		ID     string                     `json:"id"`
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	body := struct {
+		Records []update `json:"records"`
+	}{}
+	for _, id := range ids {
+		body.Records = append(body.Records, update{ID: id, Fields: changes[id].Fields})
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.Client.RequestWithBodyContext(ctx, "PATCH", url.PathEscape(table), nil, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	updated := struct {
+		Records []Record
+	}{}
+	if err := json.Unmarshal(resp, &updated); err != nil {
+		return nil, err
+	}
+	return updated.Records, nil
+}
+
+// modifiedTime reads the record's modified field, returning the zero
+// time if there isn't one.
+func modifiedTime(r Record, field string) time.Time {
+	var t time.Time
+	if field == "" {
+		return t
+	}
+	if cell, ok := r.Fields[field]; ok {
+		json.Unmarshal(cell, &t)
+	}
+	return t
+}
@@ -0,0 +1,153 @@
+package syncd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/brianloveswords/airtable"
+)
+
+// JSONL is a Destination that keeps each table in Dir as a file named
+// after the table with one record per line, sorted by ID, plus the
+// table's schema in a .schema.json file. Files are replaced atomically
+// so readers never see a partial write.
+type JSONL struct {
+	Dir string
+
+	tables map[string]map[string]Record
+}
+
+// Upsert creates or replaces records in the table's file.
+func (j *JSONL) Upsert(ctx context.Context, table string, records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	t, err := j.load(table)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		t[r.ID] = r
+	}
+	return j.save(table)
+}
+
+// Delete removes records from the table's file.
+func (j *JSONL) Delete(ctx context.Context, table string, ids []string) error {
+	t, err := j.load(table)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		delete(t, id)
+	}
+	return j.save(table)
+}
+
+// Schema writes the table's schema next to its records.
+func (j *JSONL) Schema(ctx context.Context, table airtable.TableSchema) error {
+	b, err := json.MarshalIndent(table, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeAtomic(filepath.Join(j.Dir, table.Name+".schema.json"), b)
+}
+
+// IDs lists the IDs of the records in the table's file.
+func (j *JSONL) IDs(ctx context.Context, table string) ([]string, error) {
+	t, err := j.load(table)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(t))
+	for id := range t {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (j *JSONL) path(table string) string {
+	return filepath.Join(j.Dir, table+".jsonl")
+}
+
+// load reads the table's file the first time it's needed.
+func (j *JSONL) load(table string) (map[string]Record, error) {
+	if t, ok := j.tables[table]; ok {
+		return t, nil
+	}
+	if j.tables == nil {
+		j.tables = map[string]map[string]Record{}
+	}
+	t := map[string]Record{}
+	f, err := os.Open(j.path(table))
+	if os.IsNotExist(err) {
+		j.tables[table] = t
+		return t, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, err
+		}
+		t[r.ID] = r
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	j.tables[table] = t
+	return t, nil
+}
+
+func (j *JSONL) save(table string) error {
+	t := j.tables[table]
+	ids := make([]string, 0, len(t))
+	for id := range t {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var lines []byte
+	for _, id := range ids {
+		b, err := json.Marshal(t[id])
+		if err != nil {
+			return err
+		}
+		lines = append(lines, b...)
+		lines = append(lines, '\n')
+	}
+	return writeAtomic(j.path(table), lines)
+}
+
+// writeAtomic replaces the file at path with b.
+func writeAtomic(path string, b []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,364 @@
+// Package syncd keeps Airtable tables mirrored to a destination, such as
+// a directory of JSONL files or a database reached through a
+// Destination implementation.
+//
+// A Syncer does a full load of each table the first time it runs, then
+// polls for changes. Tables with a "last modified time" field are
+// synced incrementally by asking only for records modified since the
+// last pass; other tables are reloaded in full each pass. Polling on
+// modified time can't see deletions, so incrementally synced tables are
+// also reconciled on a longer interval: the IDs of every record in the
+// table are listed, and records the destination holds that are no
+// longer in Airtable are deleted from it, as are records missing from a
+// full load. The base schema is refreshed on its own interval and
+// handed to the destination so it can adapt to new or changed fields.
+//
+// Destinations that implement ChangeSource can also have their edits
+// pushed back to Airtable. Records edited in both places since the last
+// pass are resolved with a ConflictPolicy or a custom Resolver, so
+// neither edit is silently overwritten.
+package syncd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/brianloveswords/airtable"
+)
+
+// Record is a record as it is stored in a destination. Fields holds the
+// raw JSON of each cell, keyed by Airtable field name.
+type Record struct {
+	ID          string                     `json:"id"`
+	CreatedTime time.Time                  `json:"createdTime"`
+	Fields      map[string]json.RawMessage `json:"fields"`
+}
+
+// Destination is where a Syncer mirrors tables to. Methods may be called
+// with many records at once but are never called concurrently.
+type Destination interface {
+	// Upsert creates or replaces records in the mirrored table.
+	Upsert(ctx context.Context, table string, records []Record) error
+
+	// Delete removes records from the mirrored table by ID. IDs that
+	// aren't in the table should be ignored.
+	Delete(ctx context.Context, table string, ids []string) error
+
+	// Schema is called with the table's current schema before the first
+	// sync and whenever the schema is refreshed.
+	Schema(ctx context.Context, table airtable.TableSchema) error
+}
+
+// Table configures how a table is synced.
+//
+// - Name: name of the table in the base.
+//
+// - ModifiedField: name of a "last modified time" field in the table.
+// If set, the table is synced incrementally; otherwise it's reloaded in
+// full on every pass.
+type Table struct {
+	Name          string
+	ModifiedField string
+}
+
+// Syncer mirrors tables from the client's base to a destination.
+//
+// - Client: client for the base. Required.
+//
+// - Tables: tables to mirror. Required.
+//
+// - Destination: where to mirror the tables to. Required.
+//
+// - Interval: how long Run waits between passes. Defaults to
+// DefaultInterval.
+//
+// - SchemaInterval: how often Run refreshes the schema. Defaults to
+// DefaultSchemaInterval.
+//
+// - Overlap: how far before the start of the last pass incremental
+// syncs look for changes, to allow for clock skew between this machine
+// and Airtable. Defaults to DefaultOverlap.
+//
+// - ReconcileInterval: how often incrementally synced tables are checked
+// for deleted records. Defaults to DefaultReconcileInterval.
+//
+// - ConflictPolicy: which edit wins when a record was edited both in the
+// destination and in Airtable. Defaults to LastWriterWins.
+//
+// - Resolve: resolves conflicts instead of ConflictPolicy, if set.
+//
+// Finding deleted records needs to know what the destination holds. If
+// the destination implements IDLister it's asked; otherwise the Syncer
+// remembers the records it has written since it started, so records
+// written by an earlier process are never deleted.
+type Syncer struct {
+	Client            *airtable.Client
+	Tables            []Table
+	Destination       Destination
+	Interval          time.Duration
+	SchemaInterval    time.Duration
+	Overlap           time.Duration
+	ReconcileInterval time.Duration
+	ConflictPolicy    ConflictPolicy
+	Resolve           Resolver
+
+	// lastSync is when the last pass over each table started, and
+	// lastReconcile when the table was last checked for deletions.
+	lastSync      map[string]time.Time
+	lastReconcile map[string]time.Time
+	schemaFetched time.Time
+
+	// written holds the IDs of the records written to each table, for
+	// destinations that aren't IDListers.
+	written map[string]map[string]bool
+}
+
+// Defaults for the Syncer's intervals.
+var (
+	DefaultInterval          = time.Minute
+	DefaultSchemaInterval    = time.Hour
+	DefaultOverlap           = time.Minute
+	DefaultReconcileInterval = 15 * time.Minute
+)
+
+// Run syncs the tables until ctx is done, waiting Interval between
+// passes. It returns ctx's error, or the first error from a pass.
+func (s *Syncer) Run(ctx context.Context) error {
+	interval := s.Interval
+	if interval == 0 {
+		interval = DefaultInterval
+	}
+	for {
+		if err := s.SyncOnce(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// SyncOnce does a single pass over every table, refreshing the schema
+// first if it's due.
+func (s *Syncer) SyncOnce(ctx context.Context) error {
+	if s.lastSync == nil {
+		s.lastSync = map[string]time.Time{}
+		s.lastReconcile = map[string]time.Time{}
+		s.written = map[string]map[string]bool{}
+	}
+	if err := s.refreshSchema(ctx); err != nil {
+		return err
+	}
+	for _, table := range s.Tables {
+		if err := s.syncTable(ctx, table); err != nil {
+			return fmt.Errorf("syncd: syncing %s: %s", table.Name, err)
+		}
+	}
+	return nil
+}
+
+func (s *Syncer) refreshSchema(ctx context.Context) error {
+	every := s.SchemaInterval
+	if every == 0 {
+		every = DefaultSchemaInterval
+	}
+	if !s.schemaFetched.IsZero() && time.Since(s.schemaFetched) < every {
+		return nil
+	}
+	schema, err := s.Client.Schema(ctx)
+	if err != nil {
+		return fmt.Errorf("syncd: fetching schema: %s", err)
+	}
+	for _, table := range s.Tables {
+		t := schema.Table(table.Name)
+		if t == nil {
+			return fmt.Errorf("syncd: no table %s in base", table.Name)
+		}
+		if err := s.Destination.Schema(ctx, *t); err != nil {
+			return err
+		}
+	}
+	s.schemaFetched = time.Now()
+	return nil
+}
+
+func (s *Syncer) syncTable(ctx context.Context, table Table) error {
+	started := time.Now()
+	last, synced := s.lastSync[table.Name]
+
+	query := url.Values{}
+	incremental := synced && table.ModifiedField != ""
+	if incremental {
+		overlap := s.Overlap
+		if overlap == 0 {
+			overlap = DefaultOverlap
+		}
+		query.Set("filterByFormula", modifiedSince(table.ModifiedField, last.Add(-overlap)))
+	}
+
+	changes, err := s.localChanges(ctx, table.Name)
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	written := s.written[table.Name]
+	if written == nil {
+		written = map[string]bool{}
+		s.written[table.Name] = written
+	}
+	err = listRecords(ctx, s.Client, table.Name, query, func(records []Record) error {
+		for _, r := range records {
+			seen[r.ID] = true
+		}
+		if len(changes) > 0 {
+			var err error
+			records, err = s.resolveConflicts(ctx, table, last, records, changes)
+			if err != nil {
+				return err
+			}
+		}
+		if err := s.Destination.Upsert(ctx, table.Name, records); err != nil {
+			return err
+		}
+		for _, r := range records {
+			written[r.ID] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(changes) > 0 {
+		if err := s.push(ctx, table.Name, changes, seen, !incremental); err != nil {
+			return err
+		}
+	}
+
+	// a full load sees every record, so anything the destination has
+	// that wasn't seen was deleted. Incremental syncs only see what
+	// changed, so the IDs have to be listed separately.
+	if !incremental {
+		if err := s.deleteUnseen(ctx, table.Name, seen); err != nil {
+			return err
+		}
+		s.lastReconcile[table.Name] = started
+	} else if s.reconcileDue(table.Name) {
+		if err := s.reconcile(ctx, table); err != nil {
+			return err
+		}
+	}
+	s.lastSync[table.Name] = started
+	return nil
+}
+
+func (s *Syncer) reconcileDue(table string) bool {
+	every := s.ReconcileInterval
+	if every == 0 {
+		every = DefaultReconcileInterval
+	}
+	return time.Since(s.lastReconcile[table]) >= every
+}
+
+// reconcile lists the ID of every record in the table and deletes the
+// records that are gone from the destination. Only the modified field
+// is requested to keep the pages small.
+func (s *Syncer) reconcile(ctx context.Context, table Table) error {
+	started := time.Now()
+	query := url.Values{}
+	query.Set("fields[]", table.ModifiedField)
+	seen := map[string]bool{}
+	err := listRecords(ctx, s.Client, table.Name, query, func(records []Record) error {
+		for _, r := range records {
+			seen[r.ID] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.deleteUnseen(ctx, table.Name, seen); err != nil {
+		return err
+	}
+	s.lastReconcile[table.Name] = started
+	return nil
+}
+
+// deleteUnseen removes the records the destination holds that weren't
+// seen in a listing of every record in the table.
+func (s *Syncer) deleteUnseen(ctx context.Context, table string, seen map[string]bool) error {
+	var ids []string
+	if lister, ok := s.Destination.(IDLister); ok {
+		var err error
+		if ids, err = lister.IDs(ctx, table); err != nil {
+			return err
+		}
+	} else {
+		for id := range s.written[table] {
+			ids = append(ids, id)
+		}
+	}
+	var gone []string
+	for _, id := range ids {
+		if !seen[id] {
+			gone = append(gone, id)
+		}
+	}
+	if len(gone) == 0 {
+		return nil
+	}
+	if err := s.Destination.Delete(ctx, table, gone); err != nil {
+		return err
+	}
+	for _, id := range gone {
+		delete(s.written[table], id)
+	}
+	return nil
+}
+
+// IDLister is implemented by destinations that can list the IDs of the
+// records they hold, which lets a Syncer find records that were deleted
+// in Airtable even if they were written by another process.
+type IDLister interface {
+	IDs(ctx context.Context, table string) ([]string, error)
+}
+
+// modifiedSince builds a formula matching records modified after t.
+func modifiedSince(field string, t time.Time) string {
+	return fmt.Sprintf(`IS_AFTER({%s}, DATETIME_PARSE("%s"))`, field, t.UTC().Format(time.RFC3339))
+}
+
+// listRecords pages through the table, calling fn with each page.
+func listRecords(
+	ctx context.Context,
+	client *airtable.Client,
+	table string,
+	query url.Values,
+	fn func([]Record) error,
+) error {
+	for {
+		b, err := client.RequestContext(ctx, "GET", url.PathEscape(table), query)
+		if err != nil {
+			return err
+		}
+		page := struct {
+			Records []Record
+			Offset  string
+		}{}
+		if err := json.Unmarshal(b, &page); err != nil {
+			return err
+		}
+		if err := fn(page.Records); err != nil {
+			return err
+		}
+		if page.Offset == "" {
+			return nil
+		}
+		query.Set("offset", page.Offset)
+	}
+}
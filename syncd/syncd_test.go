@@ -0,0 +1,264 @@
+package syncd_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brianloveswords/airtable"
+	"github.com/brianloveswords/airtable/syncd"
+)
+
+const schema = `{"tables": [
+	{"id": "tblA", "name": "Tasks", "fields": [{"id": "fld1", "name": "Title", "type": "singleLineText"}]},
+	{"id": "tblB", "name": "Tags", "fields": [{"id": "fld2", "name": "Name", "type": "singleLineText"}]}
+]}`
+
+func TestSyncer(t *testing.T) {
+	var (
+		tasks   = `{"id": "rec1", "fields": {"Title": "a"}}, {"id": "rec2", "fields": {"Title": "b"}}`
+		tags    = `{"id": "recT1", "fields": {"Name": "x"}}, {"id": "recT2", "fields": {"Name": "y"}}`
+		formula string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v0/meta/bases/appTEST/tables":
+			fmt.Fprint(w, schema)
+		case "/v0/appTEST/Tasks":
+			formula = r.URL.Query().Get("filterByFormula")
+			fmt.Fprintf(w, `{"records": [%s]}`, tasks)
+		case "/v0/appTEST/Tags":
+			fmt.Fprintf(w, `{"records": [%s]}`, tags)
+		}
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "syncd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	syncer := &syncd.Syncer{
+		Client: &airtable.Client{
+			APIKey:  "keyTEST",
+			BaseID:  "appTEST",
+			RootURL: server.URL,
+			Limiter: airtable.RateLimiter(0),
+		},
+		Tables: []syncd.Table{
+			{Name: "Tasks", ModifiedField: "Last Modified"},
+			{Name: "Tags"},
+		},
+		Destination: &syncd.JSONL{Dir: dir},
+	}
+	ctx := context.Background()
+
+	if err := syncer.SyncOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if formula != "" {
+		t.Errorf("expected a full load first, got formula %s", formula)
+	}
+	assertLines(t, filepath.Join(dir, "Tasks.jsonl"), "rec1", "rec2")
+	assertLines(t, filepath.Join(dir, "Tags.jsonl"), "recT1", "recT2")
+	if _, err := os.Stat(filepath.Join(dir, "Tasks.schema.json")); err != nil {
+		t.Errorf("expected schema to be written: %s", err)
+	}
+
+	// the second pass only gets changes for Tasks, and a full reload
+	// for Tags, which drops the deleted tag.
+	tasks = `{"id": "rec3", "fields": {"Title": "c"}}`
+	tags = `{"id": "recT2", "fields": {"Name": "y"}}`
+	if err := syncer.SyncOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(formula, "IS_AFTER({Last Modified}, ") {
+		t.Errorf("expected an incremental sync, got formula %q", formula)
+	}
+	assertLines(t, filepath.Join(dir, "Tasks.jsonl"), "rec1", "rec2", "rec3")
+	assertLines(t, filepath.Join(dir, "Tags.jsonl"), "recT2")
+}
+
+// assertLines checks that the JSONL file has one line per ID, in order.
+func assertLines(t *testing.T, path string, ids ...string) {
+	t.Helper()
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != len(ids) {
+		t.Fatalf("expected %d lines in %s, got %d:\n%s", len(ids), path, len(lines), b)
+	}
+	for i, id := range ids {
+		if !strings.Contains(lines[i], `"id":"`+id+`"`) {
+			t.Errorf("expected line %d of %s to be %s, got %s", i, path, id, lines[i])
+		}
+	}
+}
+
+// memoryDestination records upserts and deletes. It isn't an IDLister,
+// so the Syncer has to remember what it wrote.
+type memoryDestination struct {
+	records map[string]bool
+	deleted []string
+}
+
+func (m *memoryDestination) Upsert(ctx context.Context, table string, records []syncd.Record) error {
+	for _, r := range records {
+		m.records[r.ID] = true
+	}
+	return nil
+}
+
+func (m *memoryDestination) Delete(ctx context.Context, table string, ids []string) error {
+	for _, id := range ids {
+		delete(m.records, id)
+	}
+	m.deleted = append(m.deleted, ids...)
+	return nil
+}
+
+func (m *memoryDestination) Schema(ctx context.Context, table airtable.TableSchema) error {
+	return nil
+}
+
+func TestSyncerReconcile(t *testing.T) {
+	tasks := `{"id": "rec1", "fields": {}}, {"id": "rec2", "fields": {}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v0/meta/bases/appTEST/tables":
+			fmt.Fprint(w, schema)
+		case r.URL.Query().Get("filterByFormula") != "":
+			fmt.Fprint(w, `{"records": []}`)
+		default:
+			fmt.Fprintf(w, `{"records": [%s]}`, tasks)
+		}
+	}))
+	defer server.Close()
+
+	dest := &memoryDestination{records: map[string]bool{}}
+	syncer := &syncd.Syncer{
+		Client: &airtable.Client{
+			APIKey:  "keyTEST",
+			BaseID:  "appTEST",
+			RootURL: server.URL,
+			Limiter: airtable.RateLimiter(0),
+		},
+		Tables:            []syncd.Table{{Name: "Tasks", ModifiedField: "Last Modified"}},
+		Destination:       dest,
+		ReconcileInterval: time.Nanosecond,
+	}
+	ctx := context.Background()
+
+	if err := syncer.SyncOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	tasks = `{"id": "rec1", "fields": {}}`
+	if err := syncer.SyncOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(dest.deleted) != 1 || dest.deleted[0] != "rec2" {
+		t.Errorf("expected rec2 to be deleted, got %v", dest.deleted)
+	}
+	if !dest.records["rec1"] || len(dest.records) != 1 {
+		t.Errorf("unexpected records left %v", dest.records)
+	}
+}
+
+// changeDestination is a memoryDestination with local edits to push.
+type changeDestination struct {
+	memoryDestination
+	changes []syncd.Change
+	pushed  []string
+}
+
+func (c *changeDestination) Changes(ctx context.Context, table string) ([]syncd.Change, error) {
+	return c.changes, nil
+}
+
+func (c *changeDestination) Pushed(ctx context.Context, table string, ids []string) error {
+	c.pushed = append(c.pushed, ids...)
+	c.changes = nil
+	return nil
+}
+
+func TestSyncerConflicts(t *testing.T) {
+	var patched []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v0/meta/bases/appTEST/tables":
+			fmt.Fprint(w, schema)
+		case r.Method == "PATCH":
+			body := struct {
+				Records []struct{ ID string }
+			}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			var records []string
+			for _, r := range body.Records {
+				patched = append(patched, r.ID)
+				records = append(records, fmt.Sprintf(`{"id": %q, "fields": {"Title": "local"}}`, r.ID))
+			}
+			fmt.Fprintf(w, `{"records": [%s]}`, strings.Join(records, ","))
+		case r.URL.Query().Get("filterByFormula") != "":
+			// rec1 was edited in Airtable after the first pass.
+			fmt.Fprint(w, `{"records": [{"id": "rec1", "fields": {"Title": "remote", "Last Modified": "2100-01-01T00:00:00.000Z"}}]}`)
+		default:
+			fmt.Fprint(w, `{"records": [{"id": "rec1", "fields": {}}, {"id": "rec2", "fields": {}}]}`)
+		}
+	}))
+	defer server.Close()
+
+	dest := &changeDestination{memoryDestination: memoryDestination{records: map[string]bool{}}}
+	syncer := &syncd.Syncer{
+		Client: &airtable.Client{
+			APIKey:  "keyTEST",
+			BaseID:  "appTEST",
+			RootURL: server.URL,
+			Limiter: airtable.RateLimiter(0),
+		},
+		Tables:      []syncd.Table{{Name: "Tasks", ModifiedField: "Last Modified"}},
+		Destination: dest,
+	}
+	ctx := context.Background()
+	if err := syncer.SyncOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// both records were edited locally, but the Airtable edit to rec1
+	// is later, so only rec2 is pushed.
+	title := map[string]json.RawMessage{"Title": json.RawMessage(`"local"`)}
+	dest.changes = []syncd.Change{
+		{ID: "rec1", Fields: title, Modified: time.Now()},
+		{ID: "rec2", Fields: title, Modified: time.Now()},
+	}
+	if err := syncer.SyncOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(patched) != 1 || patched[0] != "rec2" {
+		t.Errorf("expected only rec2 to be pushed, got %v", patched)
+	}
+	if len(dest.pushed) != 2 {
+		t.Errorf("expected both changes to be dealt with, got %v", dest.pushed)
+	}
+
+	// with PreferLocal the local edit wins.
+	syncer.ConflictPolicy = syncd.PreferLocal
+	patched = nil
+	dest.changes = []syncd.Change{{ID: "rec1", Fields: title, Modified: time.Now()}}
+	if err := syncer.SyncOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(patched) != 1 || patched[0] != "rec1" {
+		t.Errorf("expected rec1 to be pushed, got %v", patched)
+	}
+}
@@ -0,0 +1,72 @@
+//go:build go1.18
+
+package airtable
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// TypedTable is a table whose records are RecordOf[T]. Its methods take
+// and return records of that type instead of interface{} arguments, so
+// the shape of the records is checked by the compiler rather than at
+// runtime. T must still be a struct, a map with string keys or a
+// json.RawMessage, as with the Fields of the records passed to Table,
+// which Go's constraints can't express, so NewTable checks it.
+type TypedTable[T any] struct {
+	table Table
+}
+
+// NewTable returns the table with the given name in the client's base,
+// for records with fields of type T. It returns an ErrInvalidArg if T
+// isn't a struct, a map with string keys or a json.RawMessage, so a
+// TypedTable's methods never fail because of T.
+func NewTable[T any](client *Client, name string) (TypedTable[T], error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if !isFieldsType(typ) {
+		return TypedTable[T]{}, ErrInvalidArg{Arg: "T", Reason: fmt.Sprintf("must be a struct, a map with string keys or a json.RawMessage, got %s", typ)}
+	}
+	return TypedTable[T]{table: client.Table(name)}, nil
+}
+
+// Table returns the untyped table, for the methods TypedTable doesn't
+// have.
+func (t TypedTable[T]) Table() Table {
+	return t.table
+}
+
+// Get returns the record with the given ID.
+func (t TypedTable[T]) Get(id string) (RecordOf[T], error) {
+	var record RecordOf[T]
+	err := t.table.Get(id, &record)
+	return record, err
+}
+
+// List returns the records matching options. See Table.List.
+func (t TypedTable[T]) List(options *Options) ([]RecordOf[T], error) {
+	return t.ListContext(context.Background(), options)
+}
+
+// ListContext is like List but the requests are bound to ctx.
+func (t TypedTable[T]) ListContext(ctx context.Context, options *Options) ([]RecordOf[T], error) {
+	records := []RecordOf[T]{}
+	err := t.table.ListContext(ctx, &records, options)
+	return records, err
+}
+
+// Create makes a new record from record and updates its ID and
+// CreatedTime. See Table.Create.
+func (t TypedTable[T]) Create(record *RecordOf[T]) error {
+	return t.table.Create(record)
+}
+
+// Update sends the fields of record to the table. See Table.Update.
+func (t TypedTable[T]) Update(record *RecordOf[T]) error {
+	return t.table.Update(record)
+}
+
+// Delete removes record from the table. See Table.Delete.
+func (t TypedTable[T]) Delete(record *RecordOf[T]) error {
+	return t.table.Delete(record)
+}
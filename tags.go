@@ -0,0 +1,155 @@
+package airtable
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// fieldTag is the airtable struct tag of a field of a Fields struct:
+//
+//	airtable:"Column Name,clearable"
+//
+// The name, if there is one, is the name of the field in Airtable and
+// takes precedence over the json tag, which is then only the name used
+// by encoding/json. Fields ignored by encoding/json with json:"-" stay
+// ignored.
+//
+// Options:
+//
+// - clearable: when the field holds its zero value, send null for it on
+// writes so Airtable clears the cell, instead of sending the zero value
+// or, with omitempty or the client's OmitZero, leaving the cell alone.
+// This is how to blank a date, number or select.
+//
+// - readonly: never send the field on writes. For computed fields, like
+// formulas, rollups, autonumbers and created times, which Airtable
+// refuses to write to. The field is still decoded from responses.
+// Fields of the types for computed fields, like CreatedBy, are readonly
+// without the tag.
+type fieldTag struct {
+	name      string
+	clearable bool
+	readonly  bool
+}
+
+// parseFieldTag parses the airtable tag of f.
+func parseFieldTag(f reflect.StructField) fieldTag {
+	var tag fieldTag
+	opts := strings.Split(f.Tag.Get("airtable"), ",")
+	tag.name = opts[0]
+	for _, opt := range opts[1:] {
+		switch strings.TrimSpace(opt) {
+		case "clearable":
+			tag.clearable = true
+		case "readonly":
+			tag.readonly = true
+		}
+	}
+	return tag
+}
+
+// readonlyField is implemented by the types of computed fields, like
+// CreatedBy, which are treated as if they were tagged readonly.
+type readonlyField interface {
+	readonlyField()
+}
+
+var readonlyFieldType = reflect.TypeOf((*readonlyField)(nil)).Elem()
+
+// absentField is implemented by field types that can be absent, like
+// Nullable, which are left out of writes when they are.
+type absentField interface {
+	absent() bool
+}
+
+// applyFieldTags applies the airtable tags of fields to b, the encoded
+// Fields struct: the cells of readonly fields, of fields whose type is
+// readonly and of absent fields are removed, and those of clearable
+// fields that hold their zero value are set to null.
+func applyFieldTags(fields reflect.Value, b []byte) ([]byte, error) {
+	if fields.Kind() != reflect.Struct {
+		return b, nil
+	}
+	var cells map[string]json.RawMessage
+	typ := fields.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" || f.Anonymous {
+			continue
+		}
+		tag := parseFieldTag(f)
+		omit := tag.readonly || f.Type.Implements(readonlyFieldType)
+		if v, ok := fields.Field(i).Interface().(absentField); ok && v.absent() {
+			omit = true
+		}
+		if !omit && !(tag.clearable && fields.Field(i).IsZero()) {
+			continue
+		}
+		name, ok := fieldName(f)
+		if !ok {
+			continue
+		}
+		if cells == nil {
+			if err := json.Unmarshal(b, &cells); err != nil {
+				return nil, err
+			}
+		}
+		if omit {
+			delete(cells, name)
+		} else {
+			cells[name] = json.RawMessage("null")
+		}
+	}
+	if cells == nil {
+		return b, nil
+	}
+	return json.Marshal(cells)
+}
+
+// fieldName returns the name of f in Airtable: the name in its airtable
+// tag, or else the key encoding/json would use. It returns false if the
+// field is ignored by encoding/json.
+func fieldName(f reflect.StructField) (string, bool) {
+	name, ok := jsonFieldName(f)
+	if !ok {
+		return "", false
+	}
+	if tag := parseFieldTag(f); tag.name != "" {
+		return tag.name, true
+	}
+	return name, true
+}
+
+// renamedFields maps the encoding/json keys of the fields of the Fields
+// struct type typ that have an airtable name to that name.
+func renamedFields(typ reflect.Type) map[string]string {
+	var renames map[string]string
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" || f.Anonymous {
+			continue
+		}
+		key, ok := jsonFieldName(f)
+		if !ok {
+			continue
+		}
+		if name := parseFieldTag(f).name; name != "" && name != key {
+			if renames == nil {
+				renames = map[string]string{}
+			}
+			renames[key] = name
+		}
+	}
+	return renames
+}
+
+// hasRenamedFields reports whether records of type typ have fields with
+// an airtable name, which encoding/json doesn't know about.
+func hasRenamedFields(typ reflect.Type) bool {
+	fields, ok := typ.FieldByName("Fields")
+	if !ok || fields.Type.Kind() != reflect.Struct {
+		return false
+	}
+	return len(renamedFields(fields.Type)) > 0
+}
@@ -0,0 +1,131 @@
+package airtable_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func recordBodies() (http.HandlerFunc, *[]string) {
+	var bodies []string
+	return func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		w.Write([]byte(`{"id": "rec1", "fields": {}}`))
+	}, &bodies
+}
+
+func TestClearableTag(t *testing.T) {
+	handler, bodies := recordBodies()
+	client, done := newTestClient(handler)
+	defer done()
+	client.OmitZero = true
+
+	type event struct {
+		airtable.Record
+		Fields struct {
+			Name  string
+			Due   time.Time `airtable:",clearable"`
+			Score float64   `json:",omitempty" airtable:",clearable"`
+		}
+	}
+	table := client.Table("Events")
+	record := event{}
+	record.ID = "rec1"
+	if err := table.Update(&record); err != nil {
+		t.Fatal(err)
+	}
+	record.Fields.Score = 2
+	if err := table.Update(&record); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`{"fields": {"Due":null,"Score":null}, "typecast": false}`,
+		`{"fields": {"Due":null,"Score":2}, "typecast": false}`,
+	}
+	for i, body := range *bodies {
+		if body != want[i] {
+			t.Errorf("unexpected body\n got: %s\nwant: %s", body, want[i])
+		}
+	}
+}
+
+func TestReadonlyTag(t *testing.T) {
+	handler, bodies := recordBodies()
+	client, done := newTestClient(handler)
+	defer done()
+
+	type book struct {
+		airtable.Record
+		Fields struct {
+			Title string
+			Score float64 `json:"Score" airtable:",readonly"`
+		}
+	}
+	table := client.Table("Books")
+	record := book{}
+	record.Fields.Title = "Dune"
+	record.Fields.Score = 4.5
+	if err := table.Create(&record); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"fields": {"Title":"Dune"}, "typecast": false}`; (*bodies)[0] != want {
+		t.Errorf("unexpected body\n got: %s\nwant: %s", (*bodies)[0], want)
+	}
+}
+
+func TestNameTag(t *testing.T) {
+	var (
+		bodies []string
+		query  string
+	)
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v0/appTEST/Tasks" {
+			query = r.URL.RawQuery
+			w.Write([]byte(`{"records": [{"id": "rec2", "fields": {"Task Title": "Test it"}}]}`))
+			return
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		w.Write([]byte(`{"id": "rec1", "fields": {"Task Title": "Ship it", "title": "wrong"}}`))
+	})
+	defer done()
+
+	type task struct {
+		airtable.Record
+		Fields struct {
+			Title string `json:"title" airtable:"Task Title"`
+			Notes string
+		}
+	}
+	table := client.Table("Tasks")
+	record := task{}
+	if err := table.Get("rec1", &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Fields.Title != "Ship it" {
+		t.Errorf("expected the title from the Task Title cell, got %q", record.Fields.Title)
+	}
+	if err := table.Update(&record); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"fields": {"Notes":"","Task Title":"Ship it"}, "typecast": false}`
+	if bodies[1] != want {
+		t.Errorf("unexpected body\n got: %s\nwant: %s", bodies[1], want)
+	}
+
+	var records []task
+	if err := table.List(&records, &airtable.Options{Fields: []string{"Title"}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Fields.Title != "Test it" {
+		t.Errorf("unexpected records %+v", records)
+	}
+	if want := "fields%5B0%5D=Task+Title"; query != want {
+		t.Errorf("unexpected query\n got: %s\nwant: %s", query, want)
+	}
+}
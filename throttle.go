@@ -0,0 +1,67 @@
+package airtable
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is the error matched by the APIError wrapped by
+// ErrClientRequest when Airtable rejects a request because the rate
+// limit was exceeded and the client is using ThrottleFailFast, or is
+// using ThrottleQueue and ran out of attempts.
+var ErrRateLimited = errors.New("airtable: rate limit exceeded")
+
+// DefaultThrottlePenalty is how long Airtable rejects requests after the
+// rate limit is exceeded.
+var DefaultThrottlePenalty = 30 * time.Second
+
+// DefaultThrottleAttempts is how many times ThrottleQueue sends a
+// request before giving up if Airtable keeps rejecting it.
+var DefaultThrottleAttempts = 5
+
+// ThrottlePolicy controls what the client does when Airtable rejects a
+// request because the rate limit was exceeded.
+type ThrottlePolicy int
+
+// ThrottleFailFast returns an error wrapping ErrRateLimited right away,
+// which suits requests made on behalf of a waiting user. ThrottleQueue
+// holds the request, and every other request made by the client, until
+// the penalty is over and then tries again, which suits batch jobs. It
+// returns the error after the client's ThrottleAttempts.
+const (
+	ThrottleFailFast ThrottlePolicy = iota
+	ThrottleQueue
+)
+
+// throttle tracks when a throttled client may make requests again.
+type throttle struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// wait blocks until the penalty, if any, is over.
+func (t *throttle) wait(ctx context.Context) error {
+	t.mu.Lock()
+	d := time.Until(t.until)
+	t.mu.Unlock()
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// penalize holds requests for the duration of the penalty.
+func (t *throttle) penalize(penalty time.Duration) {
+	t.mu.Lock()
+	if until := time.Now().Add(penalty); until.After(t.until) {
+		t.until = until
+	}
+	t.mu.Unlock()
+}
@@ -0,0 +1,126 @@
+package airtable_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func rateLimitedOnce() (http.HandlerFunc, *[]string) {
+	var bodies []string
+	return func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"errors": [{"error": "RATE_LIMIT_REACHED"}], "error": "RATE_LIMIT_REACHED"}`)
+			return
+		}
+		fmt.Fprint(w, `{"id": "recNEW", "fields": {}}`)
+	}, &bodies
+}
+
+func TestThrottleFailFast(t *testing.T) {
+	handler, bodies := rateLimitedOnce()
+	client, done := newTestClient(handler)
+	defer done()
+
+	table := client.Table("Tasks")
+	err := table.Create(&taskRecord{})
+	if !errors.Is(err, airtable.ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+	if len(*bodies) != 1 {
+		t.Errorf("expected a single request, got %d", len(*bodies))
+	}
+}
+
+func TestThrottleQueue(t *testing.T) {
+	handler, bodies := rateLimitedOnce()
+	client, done := newTestClient(handler)
+	defer done()
+	client.ThrottlePolicy = airtable.ThrottleQueue
+	client.ThrottlePenalty = 20 * time.Millisecond
+	var waits []time.Duration
+	client.OnRateLimited = func(wait time.Duration, attempt int) {
+		if attempt != len(waits)+1 {
+			t.Errorf("unexpected attempt %d", attempt)
+		}
+		waits = append(waits, wait)
+	}
+
+	table := client.Table("Tasks")
+	task := taskRecord{}
+	task.Fields.Title = "retry me"
+
+	start := time.Now()
+	if err := table.Create(&task); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("expected request to wait out the penalty, took %s", elapsed)
+	}
+	if len(*bodies) != 2 || (*bodies)[0] != (*bodies)[1] {
+		t.Errorf("expected the same body to be sent twice, got %q", *bodies)
+	}
+	if task.ID != "recNEW" {
+		t.Errorf("expected record to be created, got %+v", task)
+	}
+	if len(waits) != 1 || waits[0] != 20*time.Millisecond {
+		t.Errorf("expected OnRateLimited to be called once with the penalty, got %v", waits)
+	}
+}
+
+func TestThrottleQueueOnRetry(t *testing.T) {
+	handler, _ := rateLimitedOnce()
+	client, done := newTestClient(handler)
+	defer done()
+	client.ThrottlePolicy = airtable.ThrottleQueue
+	client.ThrottlePenalty = time.Millisecond
+	var retries []string
+	client.OnRetry = func(req airtable.RequestInfo, err error, attempt int) {
+		if !errors.Is(err, airtable.ErrRateLimited) {
+			t.Errorf("expected ErrRateLimited, got %v", err)
+		}
+		retries = append(retries, fmt.Sprintf("%s %s %d", req.Method, req.URL, attempt))
+	}
+
+	table := client.Table("Tasks")
+	if err := table.Create(&taskRecord{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(retries) != 1 || !strings.HasPrefix(retries[0], "POST ") || !strings.Contains(retries[0], "/v0/appTEST/Tasks") || !strings.HasSuffix(retries[0], " 1") {
+		t.Errorf("expected OnRetry to be called once, got %q", retries)
+	}
+}
+
+func TestThrottleQueueAttempts(t *testing.T) {
+	var requests int
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"errors": [{"error": "RATE_LIMIT_REACHED"}], "error": "RATE_LIMIT_REACHED"}`)
+	})
+	defer done()
+	client.ThrottlePolicy = airtable.ThrottleQueue
+	client.ThrottlePenalty = time.Millisecond
+	client.ThrottleAttempts = 3
+
+	table := client.Table("Tasks")
+	err := table.Create(&taskRecord{})
+	if !errors.Is(err, airtable.ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+	if _, ok := err.(airtable.ErrClientRequest); !ok {
+		t.Errorf("expected an ErrClientRequest, got %T", err)
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 attempts, got %d", requests)
+	}
+}
@@ -0,0 +1,50 @@
+package airtable_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestTransport(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "rec1", "fields": {"Title": "Write tests"}}`)
+	})
+	defer done()
+
+	var requests []*http.Request
+	client.Transport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		requests = append(requests, r)
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	table := client.Table("Tasks")
+	record := taskRecord{}
+	if err := table.Get("rec1", &record); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Create(&record); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("expected requests to go through the transport, got %d", len(requests))
+	}
+	if got := requests[0].Header.Get("Content-Type"); got != "" {
+		t.Errorf("expected GET without a Content-Type, got %q", got)
+	}
+	if got := requests[1].Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected POST with a JSON Content-Type, got %q", got)
+	}
+	if airtable.DefaultHTTPClient.Transport != nil {
+		t.Error("expected DefaultHTTPClient to be left alone")
+	}
+}
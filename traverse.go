@@ -0,0 +1,121 @@
+package airtable
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"time"
+)
+
+// Hop is one step of a traversal: follow the linked record field named
+// Field (the Airtable field name) to the records it links to in Table.
+type Hop struct {
+	Field string
+	Table Table
+}
+
+// TraversedRecord is a record reached by Traverse. Since the records
+// can come from any number of tables, the fields are decoded into a
+// generic map rather than a record struct.
+type TraversedRecord struct {
+	Table       string
+	Depth       int
+	ID          string
+	CreatedTime time.Time
+	Fields      map[string]interface{}
+}
+
+// Traverse fetches the record with ID id from the table, then follows
+// path breadth-first: the records linked from it by path[0].Field are
+// fetched from path[0].Table, the records linked from those by
+// path[1].Field are fetched from path[1].Table, and so on. All of the
+// records linked at one depth are fetched together in batches.
+//
+// Each record is fetched and returned at most once, so paths that lead
+// back to a table that was already visited don't cause loops. The
+// starting record is first in the result, at depth 0, followed by the
+// records at each depth.
+func (t *Table) Traverse(id string, path []Hop) ([]TraversedRecord, error) {
+	return t.TraverseContext(context.Background(), id, path)
+}
+
+// TraverseContext is like Traverse but the requests are bound to ctx.
+func (t *Table) TraverseContext(ctx context.Context, id string, path []Hop) ([]TraversedRecord, error) {
+	var (
+		visited = map[string]bool{}
+		result  []TraversedRecord
+	)
+	level, err := t.fetchTraversed(ctx, []string{id}, 0, visited)
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, level...)
+
+	for depth, hop := range path {
+		var ids []string
+		for _, record := range level {
+			links, _ := record.Fields[hop.Field].([]interface{})
+			for _, link := range links {
+				if id, ok := link.(string); ok {
+					ids = append(ids, id)
+				}
+			}
+		}
+		level, err = hop.Table.fetchTraversed(ctx, ids, depth+1, visited)
+		if err != nil {
+			return nil, err
+		}
+		if len(level) == 0 {
+			break
+		}
+		result = append(result, level...)
+	}
+	return result, nil
+}
+
+// fetchTraversed fetches the records in ids that haven't been visited
+// yet, in batches.
+func (t *Table) fetchTraversed(ctx context.Context, ids []string, depth int, visited map[string]bool) ([]TraversedRecord, error) {
+	var pending []string
+	for _, id := range ids {
+		key := t.name + "/" + id
+		if !visited[key] {
+			visited[key] = true
+			pending = append(pending, id)
+		}
+	}
+
+	var records []TraversedRecord
+	for start := 0; start < len(pending); start += linkBatchSize {
+		end := start + linkBatchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		query := url.Values{
+			"filterByFormula": {recordIDFormula(pending[start:end])},
+		}
+		for {
+			bytes, err := t.client.RequestContext(ctx, "GET", t.makePath(""), query)
+			if err != nil {
+				return nil, err
+			}
+			page := struct {
+				Records []TraversedRecord
+				Offset  string
+			}{}
+			if err := json.Unmarshal(bytes, &page); err != nil {
+				return nil, err
+			}
+			for _, record := range page.Records {
+				record.Table = t.name
+				record.Depth = depth
+				records = append(records, record)
+			}
+			if page.Offset == "" {
+				break
+			}
+			query.Set("offset", page.Offset)
+		}
+	}
+	return records, nil
+}
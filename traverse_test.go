@@ -0,0 +1,83 @@
+package airtable_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestTraverse(t *testing.T) {
+	records := map[string]string{
+		"Books/recBOOK":     `{"id": "recBOOK", "fields": {"Authors": ["recA1", "recA2"]}}`,
+		"Authors/recA1":     `{"id": "recA1", "fields": {"Books": ["recBOOK", "recBOOK2"]}}`,
+		"Authors/recA2":     `{"id": "recA2", "fields": {"Books": ["recBOOK"]}}`,
+		"Books/recBOOK2":    `{"id": "recBOOK2", "fields": {"Authors": ["recA1"]}}`,
+		"Publishers/recPUB": `{"id": "recPUB", "fields": {}}`,
+	}
+	var requests []string
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		table := r.URL.Path[len("/v0/appTEST/"):]
+		formula := r.URL.Query().Get("filterByFormula")
+		requests = append(requests, table+" "+formula)
+		var found []string
+		for key, record := range records {
+			var id string
+			fmt.Sscanf(key[len(table):], "/%s", &id)
+			if key[:len(table)] == table && id != "" && strings.Contains(formula, `"`+id+`"`) {
+				found = append(found, record)
+			}
+		}
+		fmt.Fprintf(w, `{"records": [%s]}`, strings.Join(found, ","))
+	})
+	defer done()
+
+	books := client.Table("Books")
+	result, err := books.Traverse("recBOOK", []airtable.Hop{
+		{Field: "Authors", Table: client.Table("Authors")},
+		{Field: "Books", Table: client.Table("Books")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, record := range result {
+		got = append(got, fmt.Sprintf("%d:%s/%s", record.Depth, record.Table, record.ID))
+	}
+	sort.Strings(got)
+	want := "[0:Books/recBOOK 1:Authors/recA1 1:Authors/recA2 2:Books/recBOOK2]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("expected %s, got %v", want, got)
+	}
+	if len(requests) != 3 {
+		t.Errorf("expected one request per depth, got %v", requests)
+	}
+}
+
+func TestTraverseContext(t *testing.T) {
+	var requests int
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"records": []}`)
+	})
+	defer done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	books := client.Table("Books")
+	_, err := books.TraverseContext(ctx, "recBOOK", []airtable.Hop{
+		{Field: "Authors", Table: client.Table("Authors")},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the context's error, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected no requests, got %d", requests)
+	}
+}
@@ -0,0 +1,76 @@
+package airtable
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+)
+
+// UntypedRecord is a record of a table there's no struct for, with its
+// cells in a Fields map keyed by Airtable field name.
+type UntypedRecord struct {
+	Record
+	Fields Fields
+}
+
+// UpdateFields sets the given fields of the record with the given ID,
+// leaving the rest of the record alone. The keys of fields are Airtable
+// field names. Unlike Update, which sends every field of the record's
+// Fields struct, only the fields in the map are sent, so there's no risk
+// of overwriting cells with zero values.
+func (t *Table) UpdateFields(id string, fields Fields) error {
+	return t.UpdateFieldsContext(context.Background(), id, fields)
+}
+
+// UpdateFieldsContext is like UpdateFields but the request is bound to
+// ctx.
+func (t *Table) UpdateFieldsContext(ctx context.Context, id string, fields Fields) error {
+	if err := t.validateFieldSelects(ctx, fields); err != nil {
+		return err
+	}
+	b, err := t.fieldsBody(fields)
+	if err != nil {
+		return err
+	}
+	_, err = t.client.RequestWithBodyContext(ctx, "PATCH", t.makePath(id), Options{}, bytes.NewReader(b))
+	t.invalidate(id)
+	return err
+}
+
+// CreateFromFields makes a new record in the table with the given
+// fields, for when there's no struct for the table's records. The keys
+// of fields are Airtable field names. The new record is returned with
+// its fields as Airtable sent them back, which includes computed fields
+// and values converted by typecast.
+func (t *Table) CreateFromFields(fields Fields) (UntypedRecord, error) {
+	return t.CreateFromFieldsContext(context.Background(), fields)
+}
+
+// CreateFromFieldsContext is like CreateFromFields but the request is
+// bound to ctx.
+func (t *Table) CreateFromFieldsContext(ctx context.Context, fields Fields) (UntypedRecord, error) {
+	if err := t.validateFieldSelects(ctx, fields); err != nil {
+		return UntypedRecord{}, err
+	}
+	b, err := t.fieldsBody(fields)
+	if err != nil {
+		return UntypedRecord{}, err
+	}
+	res, err := t.client.RequestWithBodyContext(ctx, "POST", t.makePath(""), Options{}, bytes.NewReader(b))
+	if err != nil {
+		return UntypedRecord{}, err
+	}
+	record := UntypedRecord{}
+	if err := t.client.decodeRecord(res, &record); err != nil {
+		return UntypedRecord{}, err
+	}
+	return record, nil
+}
+
+// fieldsBody is the body of a request that writes fields to a record.
+func (t *Table) fieldsBody(fields Fields) ([]byte, error) {
+	return json.Marshal(struct {
+		Fields   Fields `json:"fields"`
+		Typecast bool   `json:"typecast,omitempty"`
+	}{fields, t.typecast})
+}
@@ -0,0 +1,154 @@
+package airtable_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestUpdateFields(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		if r.Method != "PATCH" || r.URL.Path != "/v0/appTEST/Tasks/rec1" || string(b) != `{"fields":{"Done":true}}` {
+			t.Errorf("unexpected request %s %s %s", r.Method, r.URL.Path, b)
+		}
+		fmt.Fprint(w, `{"id": "rec1", "fields": {"Title": "a", "Done": true}}`)
+	})
+	defer done()
+
+	table := client.Table("Tasks")
+	if err := table.UpdateFields("rec1", airtable.Fields{"Done": true}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateFromFields(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		if r.Method != "POST" || string(b) != `{"fields":{"Title":"a"}}` {
+			t.Errorf("unexpected request %s %s", r.Method, b)
+		}
+		fmt.Fprint(w, `{"id": "recNEW", "createdTime": "2020-01-02T03:04:05.000Z", "fields": {"Title": "a"}}`)
+	})
+	defer done()
+
+	table := client.Table("Tasks")
+	record, err := table.CreateFromFields(airtable.Fields{"Title": "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.ID != "recNEW" || record.CreatedTime.IsZero() || record.Fields["Title"] != "a" {
+		t.Errorf("unexpected record %+v", record)
+	}
+}
+
+func TestSetTypecast(t *testing.T) {
+	var bodies []string
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		fmt.Fprint(w, `{"id": "rec1", "fields": {}, "records": []}`)
+	})
+	defer done()
+
+	table := client.Table("Tasks")
+	table.SetTypecast(true)
+	task := taskRecord{}
+	task.ID = "rec1"
+	if err := table.Update(&task); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.UpdateFields("rec1", airtable.Fields{"Done": "yes"}); err != nil {
+		t.Fatal(err)
+	}
+	tasks := []taskRecord{task}
+	if err := table.UpdateBatch(&tasks); err != nil {
+		t.Fatal(err)
+	}
+	for _, body := range bodies {
+		if !strings.Contains(body, `"typecast":true`) && !strings.Contains(body, `"typecast": true`) {
+			t.Errorf("expected typecast in %s", body)
+		}
+	}
+}
+
+func TestMapRecords(t *testing.T) {
+	var body string
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"records": [{"id": "rec1", "fields": {"Title": "a", "Done": true}}]}`)
+		default:
+			fmt.Fprint(w, `{"id": "rec1", "fields": {"Title": "b", "Done": true}}`)
+		}
+	})
+	defer done()
+	client.OmitZero = true
+
+	type record struct {
+		airtable.Record
+		Fields airtable.Fields
+	}
+	table := client.Table("Tasks")
+	var records []record
+	if err := table.List(&records, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Fields["Title"] != "a" || records[0].Fields["Done"] != true {
+		t.Fatalf("unexpected records %+v", records)
+	}
+
+	updated := records[0]
+	airtable.NewRecord(&updated, airtable.Fields{"Title": "b"})
+	if err := table.Update(&updated); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"fields": {"Done":true,"Title":"b"}, "typecast": false}`; body != want {
+		t.Errorf("unexpected body\n got: %s\nwant: %s", body, want)
+	}
+
+	var found []record
+	if err := table.Search("A", []string{"Title"}, &found, airtable.SearchLocal); err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 {
+		t.Errorf("expected one match, got %+v", found)
+	}
+}
+
+func TestRawRecords(t *testing.T) {
+	var body string
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		fmt.Fprint(w, `{"id": "rec1", "fields": {"Title": "a", "Tags": ["x", "y"]}}`)
+	})
+	defer done()
+
+	type record struct {
+		airtable.Record
+		Fields json.RawMessage
+	}
+	table := client.Table("Tasks")
+	got := record{}
+	if err := table.Get("rec1", &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Title": "a", "Tags": ["x", "y"]}`; string(got.Fields) != want {
+		t.Errorf("unexpected fields\n got: %s\nwant: %s", got.Fields, want)
+	}
+
+	if err := table.Update(&got); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"fields": {"Title": "a", "Tags": ["x", "y"]}, "typecast": false}`; body != want {
+		t.Errorf("unexpected body\n got: %s\nwant: %s", body, want)
+	}
+}
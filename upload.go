@@ -0,0 +1,95 @@
+package airtable
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// MaxUploadSize is the largest file the content API accepts for an
+// upload.
+const MaxUploadSize = 5 << 20
+
+// UploadFile uploads the file at path to the attachment field named
+// field, its name or ID, of the record with the given ID, through the
+// content API (see Client.ContentURL), so local files can be attached
+// without a public URL. The file is added to the field's attachments,
+// and a is set to the field's attachments after the upload.
+//
+// If contentType is empty, it's guessed from the file's extension or
+// contents. Files larger than MaxUploadSize are rejected without a
+// request.
+func (a *Attachment) UploadFile(client *Client, recordID, field, path, contentType string) error {
+	return a.UploadFileContext(context.Background(), client, recordID, field, path, contentType)
+}
+
+// UploadFileContext is like UploadFile but the request is bound to ctx.
+func (a *Attachment) UploadFileContext(ctx context.Context, client *Client, recordID, field, path, contentType string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return a.UploadContext(ctx, client, recordID, field, filepath.Base(path), f, contentType)
+}
+
+// Upload is like UploadFile but uploads the contents of r as a file
+// named filename.
+func (a *Attachment) Upload(client *Client, recordID, field, filename string, r io.Reader, contentType string) error {
+	return a.UploadContext(context.Background(), client, recordID, field, filename, r, contentType)
+}
+
+// UploadContext is like Upload but the request is bound to ctx.
+func (a *Attachment) UploadContext(ctx context.Context, client *Client, recordID, field, filename string, r io.Reader, contentType string) error {
+	client.checkSetup()
+
+	data, err := ioutil.ReadAll(io.LimitReader(r, MaxUploadSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > MaxUploadSize {
+		return fmt.Errorf("airtable: %s is larger than the %d byte upload limit", filename, MaxUploadSize)
+	}
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(filename))
+	}
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	body, err := json.Marshal(struct {
+		ContentType string `json:"contentType"`
+		File        string `json:"file"`
+		Filename    string `json:"filename"`
+	}{contentType, base64.StdEncoding.EncodeToString(data), filename})
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/%s/%s/%s/%s/uploadAttachment", client.ContentURL, client.Version,
+		client.BaseID, recordID, url.PathEscape(field))
+	res, err := client.doBytes(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	// the response has the record with only the field, keyed by its ID.
+	uploaded := struct {
+		Fields map[string]Attachment
+	}{}
+	if err := json.Unmarshal(res, &uploaded); err != nil {
+		return fmt.Errorf("airtable: could not unpack upload response %s", err)
+	}
+	for _, attachments := range uploaded.Fields {
+		*a = attachments
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package airtable_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestAttachmentUpload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.EscapedPath() != "/v0/appTEST/rec1/Cover%20Art/uploadAttachment" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+		}
+		var body struct {
+			ContentType string `json:"contentType"`
+			File        []byte `json:"file"`
+			Filename    string `json:"filename"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("unable to decode body: %s", err)
+			return
+		}
+		if body.ContentType != "text/plain; charset=utf-8" || string(body.File) != "hello" || body.Filename != "hello.txt" {
+			t.Errorf("unexpected body %+v", body)
+		}
+		fmt.Fprint(w, `{"id": "rec1", "fields": {"fldCOVER00000000": [
+			{"id": "attA", "url": "https://example.com/a.png", "filename": "a.png"},
+			{"id": "attB", "url": "https://example.com/hello.txt", "filename": "hello.txt"}
+		]}}`)
+	}))
+	defer server.Close()
+
+	client := &airtable.Client{
+		APIKey:     "keyTEST",
+		BaseID:     "appTEST",
+		ContentURL: server.URL,
+		Limiter:    airtable.RateLimiter(0),
+	}
+	dir, err := ioutil.TempDir("", "upload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "hello.txt")
+	if err := ioutil.WriteFile(path, []byte("hello"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var cover airtable.Attachment
+	if err := cover.UploadFile(client, "rec1", "Cover Art", path, ""); err != nil {
+		t.Fatal(err)
+	}
+	if len(cover) != 2 || cover[1].ID != "attB" {
+		t.Errorf("unexpected attachments %+v", cover)
+	}
+
+	large := strings.NewReader(strings.Repeat("x", airtable.MaxUploadSize+1))
+	if err := cover.Upload(client, "rec1", "Cover Art", "large.txt", large, ""); err == nil {
+		t.Error("expected an error for a file over the upload limit")
+	}
+}
@@ -0,0 +1,95 @@
+package airtable
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// UpsertResult reports what an upsert did.
+//
+// - Created: IDs of the records that were created.
+//
+// - Updated: IDs of the existing records that were updated.
+//
+// - Records: every record that was created or updated, in the order
+// they were passed in.
+type UpsertResult struct {
+	Created []string
+	Updated []string
+	Records []Record
+}
+
+// Upsert updates the records that match an existing record on the
+// fieldsToMergeOn and creates the rest. Any number of records can be
+// passed; they're sent in batches of 10 and the results of the batches
+// are combined.
+//
+// If a batch fails, Upsert stops and returns the results of the batches
+// before it along with the error.
+func (t *Table) Upsert(records []Fields, fieldsToMergeOn []string) (UpsertResult, error) {
+	return t.UpsertContext(context.Background(), records, fieldsToMergeOn)
+}
+
+// UpsertContext is like Upsert but the requests are bound to ctx.
+func (t *Table) UpsertContext(ctx context.Context, records []Fields, fieldsToMergeOn []string) (UpsertResult, error) {
+	var result UpsertResult
+	for start := 0; start < len(records); start += maxBatchSize {
+		end := start + maxBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		batch, err := t.upsertBatch(ctx, records[start:end], fieldsToMergeOn)
+		if err != nil {
+			return result, err
+		}
+		result.Created = append(result.Created, batch.Created...)
+		result.Updated = append(result.Updated, batch.Updated...)
+		result.Records = append(result.Records, batch.Records...)
+	}
+	return result, nil
+}
+
+// upsertBatch upserts up to maxBatchSize records in a single request.
+func (t *Table) upsertBatch(ctx context.Context, records []Fields, fieldsToMergeOn []string) (UpsertResult, error) {
+	type upsert struct {
+		Fields Fields `json:"fields"`
+	}
+	body := struct {
+		PerformUpsert struct {
+			FieldsToMergeOn []string `json:"fieldsToMergeOn"`
+		} `json:"performUpsert"`
+		Records  []upsert `json:"records"`
+		Typecast bool     `json:"typecast,omitempty"`
+	}{Typecast: t.typecast}
+	body.PerformUpsert.FieldsToMergeOn = fieldsToMergeOn
+	for _, fields := range records {
+		if err := t.validateFieldSelects(ctx, fields); err != nil {
+			return UpsertResult{}, err
+		}
+		body.Records = append(body.Records, upsert{Fields: fields})
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return UpsertResult{}, err
+	}
+	res, err := t.client.RequestWithBodyContext(ctx, "PATCH", t.makePath(""), Options{}, bytes.NewReader(b))
+	if err != nil {
+		return UpsertResult{}, err
+	}
+	upserted := struct {
+		Records        []Record
+		CreatedRecords []string
+		UpdatedRecords []string
+	}{}
+	if err := json.Unmarshal(res, &upserted); err != nil {
+		return UpsertResult{}, fmt.Errorf("airtable: could not unpack upsert response %s", err)
+	}
+	t.invalidate(upserted.UpdatedRecords...)
+	return UpsertResult{
+		Created: upserted.CreatedRecords,
+		Updated: upserted.UpdatedRecords,
+		Records: upserted.Records,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package airtable_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestUpsert(t *testing.T) {
+	batches := 0
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		body := struct {
+			PerformUpsert struct{ FieldsToMergeOn []string }
+			Records       []struct{ Fields map[string]string }
+		}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.Method != "PATCH" || strings.Join(body.PerformUpsert.FieldsToMergeOn, ",") != "SKU" {
+			t.Errorf("unexpected request %s %+v", r.Method, body)
+		}
+		batches++
+
+		// SKUs starting with "old" already exist.
+		var records, created, updated []string
+		for _, record := range body.Records {
+			id := "rec" + record.Fields["SKU"]
+			records = append(records, fmt.Sprintf(`{"id": %q, "fields": {}}`, id))
+			if strings.HasPrefix(record.Fields["SKU"], "old") {
+				updated = append(updated, fmt.Sprintf("%q", id))
+			} else {
+				created = append(created, fmt.Sprintf("%q", id))
+			}
+		}
+		fmt.Fprintf(w, `{"records": [%s], "createdRecords": [%s], "updatedRecords": [%s]}`,
+			strings.Join(records, ","), strings.Join(created, ","), strings.Join(updated, ","))
+	})
+	defer done()
+
+	var records []airtable.Fields
+	for i := 0; i < 12; i++ {
+		sku := fmt.Sprintf("new%d", i)
+		if i%4 == 0 {
+			sku = fmt.Sprintf("old%d", i)
+		}
+		records = append(records, airtable.Fields{"SKU": sku})
+	}
+
+	table := client.Table("Products")
+	result, err := table.Upsert(records, []string{"SKU"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batches != 2 {
+		t.Errorf("expected 2 batches, got %d", batches)
+	}
+	if len(result.Records) != 12 || len(result.Created) != 9 || len(result.Updated) != 3 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if result.Updated[2] != "recold8" || result.Records[11].ID != "recnew11" {
+		t.Errorf("expected results in order, got %+v", result)
+	}
+}
@@ -0,0 +1,136 @@
+package airtable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidChoice is returned by the methods that write records, when
+// the client validates selects, if a select field is set to a value
+// that isn't one of the field's choices.
+type ErrInvalidChoice struct {
+	Field   string
+	Value   string
+	Allowed []string
+}
+
+func (e ErrInvalidChoice) Error() string {
+	return fmt.Sprintf("airtable: %q is not a choice of field %s; allowed: %s",
+		e.Value, e.Field, strings.Join(e.Allowed, ", "))
+}
+
+// validateSelects checks the values of the select fields in the record
+// against the choices in the table's schema.
+func (t *Table) validateSelects(ctx context.Context, recordPtr interface{}) error {
+	b, err := encodeFields(reflect.ValueOf(getFields(recordPtr)))
+	if err != nil {
+		return err
+	}
+	return t.validateSelectCells(ctx, b)
+}
+
+// validateFieldSelects is like validateSelects for fields that aren't
+// in a record, like the Fields of UpdateFields, and only checks them if
+// the client validates selects.
+func (t *Table) validateFieldSelects(ctx context.Context, fields interface{}) error {
+	if !t.client.ValidateSelects {
+		return nil
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return t.validateSelectCells(ctx, b)
+}
+
+// validateSelectCells checks the select fields in b, a JSON object of
+// cells keyed by Airtable field name.
+func (t *Table) validateSelectCells(ctx context.Context, b []byte) error {
+	table, err := t.DescribeContext(ctx)
+	if err != nil {
+		return err
+	}
+	cells := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &cells); err != nil {
+		return err
+	}
+	for name, cell := range cells {
+		field := table.Field(name)
+		if field == nil || field.Options == nil {
+			continue
+		}
+		var values []string
+		switch field.Type {
+		case "singleSelect":
+			var value *string
+			if err := json.Unmarshal(cell, &value); err != nil || value == nil {
+				continue
+			}
+			values = []string{*value}
+		case "multipleSelects":
+			if err := json.Unmarshal(cell, &values); err != nil {
+				continue
+			}
+		default:
+			continue
+		}
+		allowed := choiceNames(field.Options.Choices)
+		for _, value := range values {
+			if value != "" && !containsString(allowed, value) {
+				return ErrInvalidChoice{Field: field.Name, Value: value, Allowed: allowed}
+			}
+		}
+	}
+	return nil
+}
+
+// ErrInvalidFormat is returned by Create and Update, when the client
+// validates formats, if the value of a field doesn't pass its Validate
+// method.
+type ErrInvalidFormat struct {
+	Field string
+	Err   error
+}
+
+func (e ErrInvalidFormat) Error() string {
+	return fmt.Sprintf("airtable: invalid value for field %s: %s", e.Field, e.Err)
+}
+
+func (e ErrInvalidFormat) Unwrap() error {
+	return e.Err
+}
+
+// FieldValidator is implemented by field types that can check their
+// value before it's sent, like Email, PhoneNumber and URL. Types of
+// your own can implement it too.
+type FieldValidator interface {
+	Validate() error
+}
+
+// validateFormats runs the Validate methods of the fields in the record
+// that have one.
+func validateFormats(recordPtr interface{}) error {
+	fields := reflect.ValueOf(getFields(recordPtr))
+	if fields.Kind() != reflect.Struct {
+		return nil
+	}
+	typ := fields.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		validator, ok := fields.Field(i).Interface().(FieldValidator)
+		if !ok {
+			continue
+		}
+		if err := validator.Validate(); err != nil {
+			name, _ := fieldName(f)
+			return ErrInvalidFormat{Field: name, Err: err}
+		}
+	}
+	return nil
+}
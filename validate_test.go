@@ -0,0 +1,183 @@
+package airtable_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/brianloveswords/airtable"
+)
+
+func TestValidateSelects(t *testing.T) {
+	creates := 0
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			fmt.Fprint(w, schemaResponse)
+			return
+		}
+		creates++
+		fmt.Fprint(w, `{"id": "rec1", "fields": {}}`)
+	})
+	defer done()
+	client.ValidateSelects = true
+
+	type book struct {
+		airtable.Record
+		Fields struct {
+			Title string
+			Genre string
+		}
+	}
+	table := client.Table("Books")
+
+	record := book{}
+	record.Fields.Genre = "Romance"
+	err := table.Create(&record)
+	choiceErr, ok := err.(airtable.ErrInvalidChoice)
+	if !ok {
+		t.Fatalf("expected an ErrInvalidChoice, got %v", err)
+	}
+	if choiceErr.Field != "Genre" || fmt.Sprint(choiceErr.Allowed) != "[Fantasy Sci-Fi]" {
+		t.Errorf("unexpected error %+v", choiceErr)
+	}
+	if creates != 0 {
+		t.Error("expected the record not to be sent")
+	}
+
+	record.Fields.Genre = "Sci-Fi"
+	if err := table.Create(&record); err != nil {
+		t.Fatal(err)
+	}
+	if creates != 1 {
+		t.Error("expected a valid record to be sent")
+	}
+}
+
+func TestValidateSelectsUntyped(t *testing.T) {
+	writes := 0
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			fmt.Fprint(w, schemaResponse)
+			return
+		}
+		writes++
+		fmt.Fprint(w, `{"id": "rec1", "fields": {}, "records": []}`)
+	})
+	defer done()
+	client.ValidateSelects = true
+	table := client.Table("Books")
+
+	invalid := airtable.Fields{"Title": "Dune", "Genre": "Romance"}
+	if err := table.UpdateFields("rec1", invalid); !errors.As(err, &airtable.ErrInvalidChoice{}) {
+		t.Errorf("expected UpdateFields to return an ErrInvalidChoice, got %v", err)
+	}
+	if _, err := table.CreateFromFields(invalid); !errors.As(err, &airtable.ErrInvalidChoice{}) {
+		t.Errorf("expected CreateFromFields to return an ErrInvalidChoice, got %v", err)
+	}
+	if _, err := table.Upsert([]airtable.Fields{invalid}, []string{"Title"}); !errors.As(err, &airtable.ErrInvalidChoice{}) {
+		t.Errorf("expected Upsert to return an ErrInvalidChoice, got %v", err)
+	}
+	if writes != 0 {
+		t.Errorf("expected no records to be sent, got %d writes", writes)
+	}
+	if err := table.UpdateFields("rec1", airtable.Fields{"Genre": "Fantasy"}); err != nil || writes != 1 {
+		t.Errorf("expected a valid update to be sent, got %v", err)
+	}
+}
+
+func TestValidateSelectsBindLink(t *testing.T) {
+	writes := 0
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/meta/"):
+			fmt.Fprint(w, schemaResponse)
+		case r.Method == "GET":
+			fmt.Fprint(w, `{"records": []}`)
+		default:
+			writes++
+			fmt.Fprint(w, `{"id": "rec1", "fields": {}}`)
+		}
+	})
+	defer done()
+	client.ValidateSelects = true
+
+	// the linked books are keyed by their genre, a single select, so a
+	// missing book is created with an invalid choice.
+	shelves := client.Table("Shelves")
+	shelves.BindLink("Books", airtable.LinkBinding{
+		Table:         client.Table("Books"),
+		KeyField:      "Genre",
+		CreateMissing: true,
+	})
+	type shelf struct {
+		airtable.Record
+		Fields struct {
+			Books airtable.RecordLink
+		}
+	}
+	record := shelf{}
+	record.Fields.Books = airtable.RecordLink{"Romance"}
+	if err := shelves.Create(&record); !errors.As(err, &airtable.ErrInvalidChoice{}) {
+		t.Errorf("expected an ErrInvalidChoice, got %v", err)
+	}
+	if writes != 0 {
+		t.Errorf("expected no records to be sent, got %d writes", writes)
+	}
+}
+
+func TestValidateFormats(t *testing.T) {
+	handler, bodies := recordBodies()
+	client, done := newTestClient(handler)
+	defer done()
+	client.ValidateFormats = true
+
+	type contact struct {
+		airtable.Record
+		Fields struct {
+			Email   airtable.Email
+			Phone   airtable.PhoneNumber
+			Website airtable.URL
+		}
+	}
+	table := client.Table("Contacts")
+	record := contact{}
+	record.Fields.Email = "ada@example.com"
+	record.Fields.Phone = "+1 (555) 010-9999 x12"
+	record.Fields.Website = "https://example.com"
+	if err := table.Create(&record); err != nil {
+		t.Fatal(err)
+	}
+
+	bad := []func(*contact){
+		func(c *contact) { c.Fields.Email = "Ada <ada@example.com>" },
+		func(c *contact) { c.Fields.Phone = "call me" },
+		func(c *contact) { c.Fields.Website = "example.com" },
+	}
+	for i, set := range bad {
+		invalid := record
+		set(&invalid)
+		var formatErr airtable.ErrInvalidFormat
+		if err := table.Update(&invalid); !errors.As(err, &formatErr) {
+			t.Errorf("%d: expected an ErrInvalidFormat, got %v", i, err)
+		}
+	}
+	if len(*bodies) != 1 {
+		t.Errorf("expected only the valid record to be sent, got %d requests", len(*bodies))
+	}
+
+	var decoded struct {
+		Email   airtable.Email
+		Phone   airtable.PhoneNumber
+		Website airtable.URL
+	}
+	err := json.Unmarshal([]byte(`{"Email": " Ada@Example.COM ", "Phone": "555 0100 ", "Website": null}`), &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Email != "Ada@example.com" || decoded.Phone != "555 0100" || decoded.Website != "" {
+		t.Errorf("unexpected decoded values %+v", decoded)
+	}
+}
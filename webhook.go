@@ -0,0 +1,88 @@
+package airtable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// WebhookPayload is one change notification from a webhook, as returned
+// by Client.WebhookPayloads. Cells are keyed by field ID.
+type WebhookPayload struct {
+	Timestamp             time.Time                      `json:"timestamp"`
+	BaseTransactionNumber int                            `json:"baseTransactionNumber"`
+	ActionMetadata        WebhookAction                  `json:"actionMetadata"`
+	ChangedTablesByID     map[string]WebhookTableChanges `json:"changedTablesById"`
+}
+
+// WebhookAction describes what made a change: the source (e.g. "client"
+// for a user in the Airtable UI, "publicApi" or "automation") and, when
+// there is one, the user.
+type WebhookAction struct {
+	Source         string `json:"source"`
+	SourceMetadata struct {
+		User *WebhookUser `json:"user"`
+	} `json:"sourceMetadata"`
+}
+
+// WebhookUser is the user who made a change.
+type WebhookUser struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+// WebhookTableChanges holds the changes to the records of one table.
+type WebhookTableChanges struct {
+	CreatedRecordsByID map[string]WebhookRecord       `json:"createdRecordsById"`
+	ChangedRecordsByID map[string]WebhookRecordChange `json:"changedRecordsById"`
+	DestroyedRecordIDs []string                       `json:"destroyedRecordIds"`
+}
+
+// WebhookRecord is a record created in a change.
+type WebhookRecord struct {
+	CreatedTime         time.Time                  `json:"createdTime"`
+	CellValuesByFieldID map[string]json.RawMessage `json:"cellValuesByFieldId"`
+}
+
+// WebhookRecordChange is a change to a record. Previous is only filled
+// in when the webhook asks for previous values, and only has the cells
+// that changed.
+type WebhookRecordChange struct {
+	Current  WebhookCells  `json:"current"`
+	Previous *WebhookCells `json:"previous"`
+}
+
+// WebhookCells holds cell values by field ID.
+type WebhookCells struct {
+	CellValuesByFieldID map[string]json.RawMessage `json:"cellValuesByFieldId"`
+}
+
+// WebhookPayloads lists the payloads of the webhook starting at cursor,
+// which is 1 for the first payload the webhook has. It returns the
+// cursor to pass next time, and whether there might be more payloads
+// to fetch right away.
+func (c *Client) WebhookPayloads(ctx context.Context, webhookID string, cursor int) (payloads []WebhookPayload, next int, more bool, err error) {
+	c.checkSetup()
+
+	query := url.Values{}
+	query.Set("cursor", fmt.Sprint(cursor))
+	endpoint := fmt.Sprintf("%s/%s/bases/%s/webhooks/%s/payloads?%s",
+		c.RootURL, c.Version, c.BaseID, url.PathEscape(webhookID), query.Encode())
+	bytes, err := c.doBytes(ctx, "GET", endpoint, http.NoBody)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	response := struct {
+		Payloads      []WebhookPayload `json:"payloads"`
+		Cursor        int              `json:"cursor"`
+		MightHaveMore bool             `json:"mightHaveMore"`
+	}{}
+	if err := json.Unmarshal(bytes, &response); err != nil {
+		return nil, 0, false, err
+	}
+	return response.Payloads, response.Cursor, response.MightHaveMore, nil
+}
@@ -0,0 +1,45 @@
+package airtable
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// zeroFields resets the Fields of the record pointed to by recordPtr to
+// their zero values, so fields that are missing from the response it's
+// about to be decoded from end up zero rather than keeping whatever was
+// there before.
+func zeroFields(recordPtr interface{}) {
+	v := reflect.ValueOf(recordPtr).Elem()
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	fields := v.FieldByName("Fields")
+	if fields.IsValid() && fields.CanSet() {
+		fields.Set(reflect.Zero(fields.Type()))
+	}
+}
+
+// omitZeroFields removes the cells of fields that hold their zero value
+// from b, the encoded Fields struct.
+func omitZeroFields(fields reflect.Value, b []byte) ([]byte, error) {
+	if fields.Kind() != reflect.Struct {
+		return b, nil
+	}
+	cells := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &cells); err != nil {
+		return nil, err
+	}
+	typ := fields.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" || f.Anonymous {
+			continue
+		}
+		name, ok := fieldName(f)
+		if ok && fields.Field(i).IsZero() {
+			delete(cells, name)
+		}
+	}
+	return json.Marshal(cells)
+}
@@ -0,0 +1,68 @@
+package airtable_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestZeroFill(t *testing.T) {
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		// Done is unchecked, so Airtable leaves it out.
+		fmt.Fprint(w, `{"id": "rec1", "fields": {"Title": "Write tests"}}`)
+	})
+	defer done()
+
+	table := client.Table("Tasks")
+	record := taskRecord{}
+	record.Fields.Done = true
+	if err := table.Get("rec1", &record); err != nil {
+		t.Fatal(err)
+	}
+	if !record.Fields.Done {
+		t.Error("expected Done to be left alone without ZeroFill")
+	}
+
+	client.ZeroFill = true
+	if err := table.Get("rec1", &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Fields.Done {
+		t.Error("expected Done to be zero-filled")
+	}
+}
+
+func TestOmitZero(t *testing.T) {
+	var sent map[string]interface{}
+	client, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		body := struct {
+			Fields map[string]interface{}
+		}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		sent = body.Fields
+		fmt.Fprint(w, `{"id": "rec1", "fields": {"Title": "Write tests"}}`)
+	})
+	defer done()
+
+	table := client.Table("Tasks")
+	record := taskRecord{}
+	record.Fields.Title = "Write tests"
+	if err := table.Create(&record); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sent["Done"]; !ok {
+		t.Error("expected false to be sent by default")
+	}
+
+	client.OmitZero = true
+	if err := table.Create(&record); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sent["Done"]; ok {
+		t.Error("expected false to be omitted with OmitZero")
+	}
+	if sent["Title"] != "Write tests" {
+		t.Errorf("expected Title to be sent, got %v", sent)
+	}
+}